@@ -7,11 +7,17 @@ import (
 	"time"
 
 	"github.com/wizenheimer/bloombox/pkg/emailchecker"
+	"github.com/wizenheimer/bloombox/pkg/logger"
+	"go.uber.org/zap"
 )
 
 // loadConfigFromEnv loads configuration from environment variables
 func loadConfigFromEnv() *emailchecker.Config {
 	config := emailchecker.DefaultConfig()
+	// The server runs until shutdown, so lists are watched and refreshed
+	// unless disabled
+	config.WatchLists = true
+	config.ListRefreshInterval = 24 * time.Hour
 
 	if val := os.Getenv("FREE_EMAILS_FILE"); val != "" {
 		config.FreeEmailsFile = val
@@ -31,21 +37,111 @@ func loadConfigFromEnv() *emailchecker.Config {
 	if val := os.Getenv("BLACKLIST_DOMAINS_FILE"); val != "" {
 		config.BlackListDomainsFile = val
 	}
+	if val := os.Getenv("BLACKLIST_PATTERNS_FILE"); val != "" {
+		config.BlackListPatternsFile = val
+	}
+	if val := os.Getenv("SUGGEST_DOMAINS_FILE"); val != "" {
+		config.SuggestDomainsFile = val
+	}
+	if val := os.Getenv("LIST_CACHE_DIR"); val != "" {
+		config.ListCacheDir = val
+	}
+	if val := os.Getenv("LIST_FETCH_TIMEOUT"); val != "" {
+		if timeout, err := time.ParseDuration(val); err == nil {
+			config.ListFetchTimeout = timeout
+		}
+	}
+	if val := os.Getenv("LIST_REFRESH_INTERVAL"); val != "" {
+		if interval, err := time.ParseDuration(val); err == nil {
+			config.ListRefreshInterval = interval
+		}
+	}
+	if val := os.Getenv("MAX_LIST_SIZE"); val != "" {
+		if size, err := strconv.ParseInt(val, 10, 64); err == nil {
+			config.MaxListSize = size
+		}
+	}
+	if val := os.Getenv("STRICT_LISTS"); val != "" {
+		if strict, err := strconv.ParseBool(val); err == nil {
+			config.StrictLists = strict
+		}
+	}
+	if val := os.Getenv("MAX_LIST_ERROR_RATE"); val != "" {
+		if rate, err := strconv.ParseFloat(val, 64); err == nil {
+			config.MaxListErrorRate = rate
+		}
+	}
+	if val := os.Getenv("WATCH_LISTS"); val != "" {
+		if watch, err := strconv.ParseBool(val); err == nil {
+			config.WatchLists = watch
+		}
+	}
+	if val := os.Getenv("LIST_POLL_INTERVAL"); val != "" {
+		if interval, err := time.ParseDuration(val); err == nil {
+			config.ListPollInterval = interval
+		}
+	}
 	if val := os.Getenv("FALSE_POSITIVE_RATE"); val != "" {
 		if rate, err := strconv.ParseFloat(val, 64); err == nil {
 			config.FalsePositiveRate = rate
 		}
 	}
+	if val := os.Getenv("FILTER_TYPES"); val != "" {
+		config.FilterTypes = make(map[string]emailchecker.FilterType)
+		for _, pair := range strings.Split(val, ",") {
+			name, filterType, ok := strings.Cut(pair, "=")
+			if !ok {
+				logger.Fatal("Invalid filter type, expected list=type", zap.String("value", pair))
+			}
+			config.FilterTypes[strings.TrimSpace(name)] = emailchecker.FilterType(strings.TrimSpace(filterType))
+		}
+	}
+	if val := os.Getenv("SNAPSHOT_DIR"); val != "" {
+		config.SnapshotDir = val
+	}
 	if val := os.Getenv("CACHE_SIZE"); val != "" {
 		if size, err := strconv.Atoi(val); err == nil {
 			config.CacheSize = size
 		}
 	}
+	if val := os.Getenv("CACHE_FILE"); val != "" {
+		config.CacheFile = val
+	}
+	if val := os.Getenv("CACHE_TIMEOUT"); val != "" {
+		if timeout, err := time.ParseDuration(val); err == nil {
+			config.CacheTimeout = timeout
+		}
+	}
+	if val := os.Getenv("DOMAIN_CACHE_SIZE"); val != "" {
+		if size, err := strconv.Atoi(val); err == nil {
+			config.DomainCacheSize = size
+		}
+	}
+	if val := os.Getenv("DOMAIN_CACHE_TTL"); val != "" {
+		if ttl, err := time.ParseDuration(val); err == nil {
+			config.DomainCacheTTL = ttl
+		}
+	}
 	if val := os.Getenv("VALIDATION_TIMEOUT"); val != "" {
 		if timeout, err := time.ParseDuration(val); err == nil {
 			config.ValidationTimeout = timeout
 		}
 	}
+	if val := os.Getenv("MAX_CONCURRENT_VALIDATIONS"); val != "" {
+		if limit, err := strconv.Atoi(val); err == nil {
+			config.MaxConcurrentValidations = limit
+		}
+	}
+	if val := os.Getenv("MAX_QUEUE_LENGTH"); val != "" {
+		if length, err := strconv.Atoi(val); err == nil {
+			config.MaxQueueLength = length
+		}
+	}
+	if val := os.Getenv("MAX_QUEUE_WAIT"); val != "" {
+		if wait, err := time.ParseDuration(val); err == nil {
+			config.MaxQueueWait = wait
+		}
+	}
 	if val := os.Getenv("SMTP_TIMEOUT"); val != "" {
 		if timeout, err := time.ParseDuration(val); err == nil {
 			config.SMTPTimeout = timeout
@@ -57,9 +153,26 @@ func loadConfigFromEnv() *emailchecker.Config {
 	if val := os.Getenv("SMTP_FROM_EMAIL"); val != "" {
 		config.SMTPFromEmail = val
 	}
+	if val := os.Getenv("SCORE_DELIVERABLE_THRESHOLD"); val != "" {
+		if threshold, err := strconv.ParseFloat(val, 64); err == nil {
+			config.ScoreThresholds.Deliverable = threshold
+		}
+	}
+	if val := os.Getenv("SCORE_RISKY_THRESHOLD"); val != "" {
+		if threshold, err := strconv.ParseFloat(val, 64); err == nil {
+			config.ScoreThresholds.Risky = threshold
+		}
+	}
 	if val := os.Getenv("ENABLED_VALIDATORS"); val != "" {
 		config.EnabledValidators = strings.Split(val, ",")
 	}
+	if val := os.Getenv("POLICIES_FILE"); val != "" {
+		policies, err := emailchecker.LoadPolicies(val)
+		if err != nil {
+			logger.Fatal("Failed to load policies", zap.String("file", val), zap.Error(err))
+		}
+		config.Policies = policies
+	}
 
 	return config
 }
@@ -26,6 +26,8 @@ func (s *Server) handleIndex(w http.ResponseWriter, r *http.Request) {
 			"POST /batch":           "Validate multiple email addresses",
 			"GET /validators":       "List available validators",
 			"PUT /validators/:name": "Enable/disable specific validator",
+			"GET /policies":         "List validation policies",
+			"GET /lists":            "Load reports of list files",
 			"GET /health":           "Health check endpoint",
 		},
 		"validators": s.checker.GetValidators(),
@@ -50,13 +52,13 @@ func (s *Server) handleValidate(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	var result *emailchecker.CheckResult
-	if len(req.Validators) > 0 {
-		result = s.checker.CheckWithValidators(req.Email, req.Validators)
-	} else {
-		result = s.checker.Check(req.Email)
+	if !s.knownPolicy(w, req.Policy) {
+		return
 	}
 
+	// Tie the check to the client connection so a disconnect cancels it
+	result := s.checker.CheckContext(r.Context(), req.Email, req.Options())
+
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(result)
 }
@@ -71,7 +73,13 @@ func (s *Server) handleBatch(w http.ResponseWriter, r *http.Request) {
 	var req struct {
 		Emails     []string `json:"emails"`
 		Validators []string `json:"validators,omitempty"`
-		Timeout    int      `json:"timeout,omitempty"`
+		Timeout    int      `json:"timeout,omitempty"` // Timeout in seconds for each email
+
+		Thresholds *emailchecker.ScoreThresholds `json:"thresholds,omitempty"`
+		Priority   emailchecker.Priority         `json:"priority,omitempty"`
+		Policy     string                        `json:"policy,omitempty"`
+		Enable     []string                      `json:"enable,omitempty"`
+		Disable    []string                      `json:"disable,omitempty"`
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -89,32 +97,45 @@ func (s *Server) handleBatch(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	results := make([]*emailchecker.CheckResult, len(req.Emails))
+	if !s.knownPolicy(w, req.Policy) {
+		return
+	}
 
-	// Process emails concurrently
-	type result struct {
-		index int
-		data  *emailchecker.CheckResult
+	// The timeout bounds each email like it does a single validation, a
+	// deadline for the whole batch would drop the results it cuts off
+	opts := emailchecker.StreamOptions{
+		CheckOptions: emailchecker.CheckOptions{
+			Validators: req.Validators,
+			Timeout:    time.Duration(req.Timeout) * time.Second,
+			Thresholds: req.Thresholds,
+			Priority:   req.Priority,
+			Policy:     req.Policy,
+			Enable:     req.Enable,
+			Disable:    req.Disable,
+		},
+		Ordered: true,
 	}
 
-	resultChan := make(chan result, len(req.Emails))
+	// Batches yield to single validations under load unless told otherwise
+	if opts.Priority == "" {
+		opts.Priority = emailchecker.PriorityLow
+	}
 
-	for i, email := range req.Emails {
-		go func(idx int, em string) {
-			var res *emailchecker.CheckResult
-			if len(req.Validators) > 0 {
-				res = s.checker.CheckWithValidators(em, req.Validators)
-			} else {
-				res = s.checker.Check(em)
+	emails := make(chan string)
+	go func() {
+		defer close(emails)
+		for _, email := range req.Emails {
+			select {
+			case emails <- email:
+			case <-r.Context().Done():
+				return
 			}
-			resultChan <- result{index: idx, data: res}
-		}(i, email)
-	}
+		}
+	}()
 
-	// Collect results
-	for i := 0; i < len(req.Emails); i++ {
-		res := <-resultChan
-		results[res.index] = res.data
+	results := make([]*emailchecker.CheckResult, 0, len(req.Emails))
+	for res := range s.checker.CheckStream(r.Context(), emails, opts) {
+		results = append(results, res)
 	}
 
 	response := map[string]interface{}{
@@ -126,6 +147,8 @@ func (s *Server) handleBatch(w http.ResponseWriter, r *http.Request) {
 			"disposable": countDisposable(results),
 			"free":       countFree(results),
 			"role":       countRole(results),
+			"duplicates": countDuplicates(results),
+			"verdicts":   countVerdicts(results),
 		},
 	}
 
@@ -145,7 +168,7 @@ func (s *Server) handleValidators(w http.ResponseWriter, r *http.Request) {
 
 	case http.MethodPut:
 		// Parse validator name from URL path
-		path := strings.TrimPrefix(r.URL.Path, "/validators/")
+		path := strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, "/validators"), "/")
 		if path == "" {
 			http.Error(w, "Validator name is required", http.StatusBadRequest)
 			return
@@ -184,6 +207,45 @@ func (s *Server) handleValidators(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// handlePolicies lists the configured validation policies
+func (s *Server) handlePolicies(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"policies": s.checker.Policies(),
+	})
+}
+
+// handleLists reports how each list file was loaded, with its rejected
+// entries
+func (s *Server) handleLists(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"lists": s.checker.LoadReports(),
+	})
+}
+
+// knownPolicy rejects requests selecting a policy that is not configured
+func (s *Server) knownPolicy(w http.ResponseWriter, name string) bool {
+	if name == "" {
+		return true
+	}
+	if _, ok := s.checker.Policy(name); !ok {
+		http.Error(w, fmt.Sprintf("Unknown policy: %s", name), http.StatusBadRequest)
+		return false
+	}
+	return true
+}
+
 // handleHealth handles health check requests
 func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
@@ -199,12 +261,17 @@ func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	stats := s.checker.Stats()
+
 	health := map[string]interface{}{
 		"status":             "healthy",
 		"timestamp":          time.Now(),
 		"enabled_validators": enabledCount,
 		"total_validators":   len(validators),
 		"cache_size":         s.config.CacheSize,
+		"validations":        stats.Limiter,
+		"domain_cache":       stats.DomainCache,
+		"filters":            stats.Filters,
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/wizenheimer/bloombox/pkg/emailchecker"
+)
+
+func TestHandleValidatorsPut(t *testing.T) {
+	config := emailchecker.DefaultConfig()
+	checker, err := emailchecker.New(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer checker.Close()
+
+	server := NewServer(checker, config)
+	server.SetupRoutes()
+	handler := server.GetHandler()
+
+	tests := []struct {
+		name        string
+		path        string
+		body        string
+		wantStatus  int
+		wantEnabled bool
+	}{
+		{"disable", "/validators/syntax", `{"enabled": false}`, http.StatusOK, false},
+		{"enable", "/validators/syntax", `{"enabled": true}`, http.StatusOK, true},
+		{"unknown validator", "/validators/missing", `{"enabled": true}`, http.StatusNotFound, true},
+		{"no name", "/validators/", `{"enabled": false}`, http.StatusBadRequest, true},
+		{"bad body", "/validators/syntax", `enabled`, http.StatusBadRequest, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPut, tt.path, strings.NewReader(tt.body))
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body)
+			}
+			if enabled := checker.GetValidators()["syntax"]; enabled != tt.wantEnabled {
+				t.Errorf("syntax enabled = %v, want %v", enabled, tt.wantEnabled)
+			}
+		})
+	}
+}
@@ -17,6 +17,7 @@ func main() {
 	if err != nil {
 		logger.Fatal("Failed to initialize email checker", zap.Error(err))
 	}
+	defer checker.Close()
 
 	server := NewServer(checker, config)
 	server.SetupRoutes()
@@ -33,6 +34,8 @@ func main() {
 	logger.Info("  POST /batch            - Validate multiple emails")
 	logger.Info("  GET  /validators       - List available validators")
 	logger.Info("  PUT  /validators/:name - Enable/disable validator")
+	logger.Info("  GET  /policies         - List validation policies")
+	logger.Info("  GET  /lists            - Load reports of list files")
 	logger.Info("  GET  /health           - Health check")
 
 	handler := server.GetHandler()
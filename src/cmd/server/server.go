@@ -26,6 +26,9 @@ func (s *Server) SetupRoutes() {
 	http.HandleFunc("/validate", s.handleValidate)
 	http.HandleFunc("/batch", s.handleBatch)
 	http.HandleFunc("/validators", s.handleValidators)
+	http.HandleFunc("/validators/", s.handleValidators) // PUT /validators/:name
+	http.HandleFunc("/policies", s.handlePolicies)
+	http.HandleFunc("/lists", s.handleLists)
 	http.HandleFunc("/health", s.handleHealth)
 }
 
@@ -45,3 +45,25 @@ func countRole(results []*emailchecker.CheckResult) int {
 	}
 	return count
 }
+
+// countVerdicts counts the number of emails per verdict in the results
+func countVerdicts(results []*emailchecker.CheckResult) map[emailchecker.Verdict]int {
+	counts := make(map[emailchecker.Verdict]int)
+	for _, result := range results {
+		counts[result.Verdict]++
+	}
+	return counts
+}
+
+// countDuplicates counts the emails whose canonical form appeared earlier in the results
+func countDuplicates(results []*emailchecker.CheckResult) int {
+	seen := make(map[string]bool)
+	count := 0
+	for _, result := range results {
+		if seen[result.CanonicalEmail] {
+			count++
+		}
+		seen[result.CanonicalEmail] = true
+	}
+	return count
+}
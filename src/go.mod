@@ -5,10 +5,15 @@ go 1.24.2
 require (
 	github.com/hashicorp/golang-lru/v2 v2.0.7
 	github.com/linvon/cuckoo-filter v0.4.0
+	go.etcd.io/bbolt v1.4.3
+	go.uber.org/zap v1.27.0
+	golang.org/x/net v0.47.0
+	golang.org/x/sync v0.18.0
+	golang.org/x/sys v0.38.0
 )
 
 require (
 	github.com/dgryski/go-metro v0.0.0-20200812162917-85c65e2d0165 // indirect
 	go.uber.org/multierr v1.10.0 // indirect
-	go.uber.org/zap v1.27.0 // indirect
+	golang.org/x/text v0.31.0 // indirect
 )
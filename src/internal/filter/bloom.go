@@ -0,0 +1,211 @@
+package filter
+
+import (
+	"encoding/binary"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"math/bits"
+	"sync"
+)
+
+// BloomFilter implements Filter using a Bloom filter sized from the
+// expected number of items and the target false positive rate
+type BloomFilter struct {
+	bits      []uint64
+	numBits   uint64
+	numHashes uint64
+	size      int
+	capacity  int
+	mu        sync.RWMutex
+}
+
+// maxBloomHashes bounds the hash count of a decoded filter, well above the
+// count bloomParameters picks for the lowest false positive rate, so a
+// corrupt snapshot can't make every lookup hash without end
+const maxBloomHashes = 64
+
+// NewBloomFilter creates a new Bloom filter holding up to capacity items
+// with the given false positive rate
+func NewBloomFilter(capacity int, falsePositiveRate float64) *BloomFilter {
+	numBits, numHashes := bloomParameters(capacity, falsePositiveRate)
+
+	return &BloomFilter{
+		bits:      make([]uint64, (numBits+63)/64),
+		numBits:   numBits,
+		numHashes: numHashes,
+		capacity:  capacity,
+	}
+}
+
+// bloomParameters computes the optimal number of bits m = -n ln p / (ln 2)^2
+// and hash functions k = m/n ln 2 for n items at false positive rate p
+func bloomParameters(capacity int, falsePositiveRate float64) (uint64, uint64) {
+	n := math.Max(float64(capacity), 1)
+	p := math.Min(math.Max(falsePositiveRate, 1e-9), 0.5)
+
+	m := math.Ceil(-n * math.Log(p) / (math.Ln2 * math.Ln2))
+	k := math.Round(m / n * math.Ln2)
+
+	return uint64(math.Max(m, 64)), uint64(math.Max(k, 1))
+}
+
+// locations returns the two hashes of an item from which its bit positions
+// are derived with double hashing, the second one is the FNV hash passed
+// through the splitmix64 finalizer to spread it over all 64 bits
+func (bf *BloomFilter) locations(item string) (uint64, uint64) {
+	h := fnv.New64a()
+	h.Write([]byte(item))
+	h1 := h.Sum64()
+
+	h2 := h1
+	h2 = (h2 ^ (h2 >> 30)) * 0xbf58476d1ce4e5b9
+	h2 = (h2 ^ (h2 >> 27)) * 0x94d049bb133111eb
+	h2 ^= h2 >> 31
+	return h1, h2 | 1
+}
+
+// Add adds an item to the filter
+func (bf *BloomFilter) Add(item string) error {
+	h1, h2 := bf.locations(item)
+
+	bf.mu.Lock()
+	defer bf.mu.Unlock()
+
+	added := false
+	for i := uint64(0); i < bf.numHashes; i++ {
+		bit := (h1 + i*h2) % bf.numBits
+		word, mask := bit/64, uint64(1)<<(bit%64)
+		if bf.bits[word]&mask == 0 {
+			bf.bits[word] |= mask
+			added = true
+		}
+	}
+
+	// Items whose bits were all set already are likely duplicates
+	if added {
+		bf.size++
+	}
+	return nil
+}
+
+// Contains checks if an item may exist in the filter
+func (bf *BloomFilter) Contains(item string) bool {
+	h1, h2 := bf.locations(item)
+
+	bf.mu.RLock()
+	defer bf.mu.RUnlock()
+
+	for i := uint64(0); i < bf.numHashes; i++ {
+		bit := (h1 + i*h2) % bf.numBits
+		if bf.bits[bit/64]&(uint64(1)<<(bit%64)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Size returns the approximate number of items in the filter
+func (bf *BloomFilter) Size() int {
+	bf.mu.RLock()
+	defer bf.mu.RUnlock()
+
+	return bf.size
+}
+
+// Stats reports the share of bits set and the false positive rate they
+// give, (bits set / bits)^k
+func (bf *BloomFilter) Stats() Stats {
+	bf.mu.RLock()
+	defer bf.mu.RUnlock()
+
+	set := 0
+	for _, word := range bf.bits {
+		set += bits.OnesCount64(word)
+	}
+	fill := float64(set) / float64(bf.numBits)
+
+	return Stats{
+		Type:              TypeBloom,
+		Items:             bf.size,
+		Capacity:          bf.capacity,
+		Stages:            1,
+		FillRatio:         fill,
+		FalsePositiveRate: math.Pow(fill, float64(bf.numHashes)),
+		MemoryBytes:       8 * len(bf.bits),
+	}
+}
+
+// Delete is not supported by Bloom filters and always returns false
+func (bf *BloomFilter) Delete(item string) bool {
+	return false
+}
+
+// Clear removes all items from the filter
+func (bf *BloomFilter) Clear() {
+	bf.mu.Lock()
+	defer bf.mu.Unlock()
+
+	clear(bf.bits)
+	bf.size = 0
+}
+
+// GetAll returns all items in the filter
+// Note: Bloom filters don't support enumeration, so this returns an empty slice
+func (bf *BloomFilter) GetAll() []string {
+	return []string{}
+}
+
+// MarshalBinary encodes the filter as its parameters followed by its bits
+func (bf *BloomFilter) MarshalBinary() ([]byte, error) {
+	bf.mu.RLock()
+	defer bf.mu.RUnlock()
+
+	buf := make([]byte, 32, 32+8*len(bf.bits))
+	binary.LittleEndian.PutUint64(buf[0:], bf.numBits)
+	binary.LittleEndian.PutUint64(buf[8:], bf.numHashes)
+	binary.LittleEndian.PutUint64(buf[16:], uint64(bf.size))
+	binary.LittleEndian.PutUint64(buf[24:], uint64(bf.capacity))
+	for _, word := range bf.bits {
+		buf = binary.LittleEndian.AppendUint64(buf, word)
+	}
+	return buf, nil
+}
+
+// UnmarshalBinary restores a filter encoded by MarshalBinary
+func (bf *BloomFilter) UnmarshalBinary(data []byte) error {
+	if len(data) < 32 {
+		return fmt.Errorf("bloom filter data too short: %d bytes", len(data))
+	}
+	numBits := binary.LittleEndian.Uint64(data[0:])
+	numHashes := binary.LittleEndian.Uint64(data[8:])
+	size := binary.LittleEndian.Uint64(data[16:])
+	capacity := binary.LittleEndian.Uint64(data[24:])
+
+	// The bits must fill the last word, compared without rounding numBits
+	// up so a crafted count can't overflow into a match
+	words := data[32:]
+	numWords := uint64(len(words) / 8)
+	if numHashes == 0 || numHashes > maxBloomHashes {
+		return fmt.Errorf("invalid bloom filter data: %d hash functions", numHashes)
+	}
+	if numBits == 0 || len(words)%8 != 0 ||
+		numBits > numWords*64 || numBits <= (numWords-1)*64 {
+		return fmt.Errorf("invalid bloom filter data: %d bits in %d bytes", numBits, len(words))
+	}
+
+	bits := make([]uint64, len(words)/8)
+	for i := range bits {
+		bits[i] = binary.LittleEndian.Uint64(words[i*8:])
+	}
+
+	bf.mu.Lock()
+	defer bf.mu.Unlock()
+
+	bf.bits = bits
+	bf.numBits = numBits
+	bf.numHashes = numHashes
+	bf.size = int(size)
+	bf.capacity = int(capacity)
+	return nil
+}
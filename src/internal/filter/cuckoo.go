@@ -1,7 +1,9 @@
 package filter
 
 import (
+	"encoding/binary"
 	"fmt"
+	"math"
 	"sync"
 
 	cuckoo "github.com/linvon/cuckoo-filter"
@@ -9,29 +11,45 @@ import (
 
 // CuckooFilter implements Filter using cuckoo filter
 type CuckooFilter struct {
-	filter   *cuckoo.Filter
-	size     int
-	capacity int
-	mu       sync.RWMutex
+	filter      *cuckoo.Filter
+	size        int
+	capacity    int
+	bitsPerItem uint
+	mu          sync.RWMutex
 }
 
-// NewCuckooFilter creates a new cuckoo filter
-func NewCuckooFilter(capacity int, falsePositiveRate float64) *CuckooFilter {
-	// Calculate optimal parameters for cuckoo filter
-	bucketSize := uint(4) // Standard bucket size
-
-	// Adjust capacity based on false positive rate
-	adjustedCapacity := int(float64(capacity) * 1.2) // Add 20% buffer
+// cuckooTagsPerBucket is the number of fingerprints per bucket, 4 gives
+// the best space efficiency for false positive rates above 0.002%
+const cuckooTagsPerBucket = 4
 
-	filter := cuckoo.NewFilter(uint(adjustedCapacity), bucketSize, uint(capacity), cuckoo.TableTypeSingle)
+// NewCuckooFilter creates a new cuckoo filter holding up to capacity items
+// with the given false positive rate
+func NewCuckooFilter(capacity int, falsePositiveRate float64) *CuckooFilter {
+	// Add 20% buffer, inserts fail as the table gets close to full
+	adjustedCapacity := max(int(float64(capacity)*1.2), cuckooTagsPerBucket)
+	bitsPerItem := cuckooFingerprintBits(falsePositiveRate)
 
 	return &CuckooFilter{
-		filter:   filter,
-		size:     0,
-		capacity: adjustedCapacity,
+		filter:      newCuckoo(adjustedCapacity, bitsPerItem),
+		size:        0,
+		capacity:    adjustedCapacity,
+		bitsPerItem: bitsPerItem,
 	}
 }
 
+// cuckooFingerprintBits returns the fingerprint size f giving a false
+// positive rate of at most p with b tags per bucket: f >= log2(2b/p)
+func cuckooFingerprintBits(falsePositiveRate float64) uint {
+	p := math.Min(math.Max(falsePositiveRate, 1e-9), 0.5)
+	bits := math.Ceil(math.Log2(2 * cuckooTagsPerBucket / p))
+	return uint(math.Min(math.Max(bits, 4), 32))
+}
+
+// newCuckoo creates the underlying cuckoo filter
+func newCuckoo(capacity int, bitsPerItem uint) *cuckoo.Filter {
+	return cuckoo.NewFilter(cuckooTagsPerBucket, bitsPerItem, uint(capacity), cuckoo.TableTypeSingle)
+}
+
 // Add adds an item to the filter
 func (cf *CuckooFilter) Add(item string) error {
 	cf.mu.Lock()
@@ -60,6 +78,27 @@ func (cf *CuckooFilter) Size() int {
 	return cf.size
 }
 
+// Stats reports the share of fingerprint slots in use and the false
+// positive rate they give, 1 - (1 - 2^-f)^(2b * load) with b tags per
+// bucket and f bits per fingerprint
+func (cf *CuckooFilter) Stats() Stats {
+	cf.mu.RLock()
+	defer cf.mu.RUnlock()
+
+	load := cf.filter.LoadFactor()
+	miss := 1 - math.Pow(2, -float64(cf.bitsPerItem))
+
+	return Stats{
+		Type:              TypeCuckoo,
+		Items:             cf.size,
+		Capacity:          cf.capacity,
+		Stages:            1,
+		FillRatio:         load,
+		FalsePositiveRate: 1 - math.Pow(miss, 2*cuckooTagsPerBucket*load),
+		MemoryBytes:       int(cf.filter.SizeInBytes()),
+	}
+}
+
 // Delete removes an item from the filter (if supported)
 func (cf *CuckooFilter) Delete(item string) bool {
 	cf.mu.Lock()
@@ -79,8 +118,7 @@ func (cf *CuckooFilter) Clear() {
 	defer cf.mu.Unlock()
 
 	// Recreate the filter with the same parameters
-	bucketSize := uint(4)
-	cf.filter = cuckoo.NewFilter(uint(cf.capacity), bucketSize, uint(cf.capacity), cuckoo.TableTypeSingle)
+	cf.filter = newCuckoo(cf.capacity, cf.bitsPerItem)
 	cf.size = 0
 }
 
@@ -91,3 +129,41 @@ func (cf *CuckooFilter) GetAll() []string {
 	// This is a limitation of the data structure
 	return []string{}
 }
+
+// MarshalBinary encodes the filter as its parameters followed by the
+// encoded cuckoo table
+func (cf *CuckooFilter) MarshalBinary() ([]byte, error) {
+	cf.mu.RLock()
+	defer cf.mu.RUnlock()
+
+	table, err := cf.filter.Encode()
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode cuckoo filter: %w", err)
+	}
+
+	buf := make([]byte, 20, 20+len(table))
+	binary.LittleEndian.PutUint64(buf[0:], uint64(cf.size))
+	binary.LittleEndian.PutUint64(buf[8:], uint64(cf.capacity))
+	binary.LittleEndian.PutUint32(buf[16:], uint32(cf.bitsPerItem))
+	return append(buf, table...), nil
+}
+
+// UnmarshalBinary restores a filter encoded by MarshalBinary
+func (cf *CuckooFilter) UnmarshalBinary(data []byte) error {
+	if len(data) < 20 {
+		return fmt.Errorf("cuckoo filter data too short: %d bytes", len(data))
+	}
+	table, err := cuckoo.Decode(data[20:])
+	if err != nil {
+		return fmt.Errorf("failed to decode cuckoo filter: %w", err)
+	}
+
+	cf.mu.Lock()
+	defer cf.mu.Unlock()
+
+	cf.filter = table
+	cf.size = int(binary.LittleEndian.Uint64(data[0:]))
+	cf.capacity = int(binary.LittleEndian.Uint64(data[8:]))
+	cf.bitsPerItem = uint(binary.LittleEndian.Uint32(data[16:]))
+	return nil
+}
@@ -0,0 +1,160 @@
+package filter
+
+import (
+	"encoding/binary"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// exactBlockSize is the number of items per front-coded block, larger
+// blocks compress better but lookups scan more items
+const exactBlockSize = 16
+
+// ExactSet is a compact, immutable set of strings stored sorted and
+// front-coded: each block starts with a full item, and the following items
+// keep only the suffix that differs from the previous one. Lookups binary
+// search the block heads and scan one block.
+type ExactSet struct {
+	data   []byte
+	blocks []uint32 // Offset of each block in data
+	count  int
+}
+
+// NewExactSet creates an exact set holding items
+func NewExactSet(items []string) *ExactSet {
+	sorted := slices.Clone(items)
+	slices.Sort(sorted)
+	sorted = slices.Compact(sorted)
+
+	es := &ExactSet{count: len(sorted)}
+	prev := ""
+	for i, item := range sorted {
+		if i%exactBlockSize == 0 {
+			es.blocks = append(es.blocks, uint32(len(es.data)))
+			prev = ""
+		}
+		shared := commonPrefix(prev, item)
+		es.data = binary.AppendUvarint(es.data, uint64(shared))
+		es.data = binary.AppendUvarint(es.data, uint64(len(item)-shared))
+		es.data = append(es.data, item[shared:]...)
+		prev = item
+	}
+	return es
+}
+
+// commonPrefix returns the length of the longest common prefix of a and b
+func commonPrefix(a, b string) int {
+	n := min(len(a), len(b))
+	for i := 0; i < n; i++ {
+		if a[i] != b[i] {
+			return i
+		}
+	}
+	return n
+}
+
+// next decodes the item at offset given the previous item in its block
+// and returns it with the offset of the following item
+func (es *ExactSet) next(offset int, prev string) (string, int) {
+	shared, n := binary.Uvarint(es.data[offset:])
+	offset += n
+	length, n := binary.Uvarint(es.data[offset:])
+	offset += n
+	end := offset + int(length)
+	return prev[:shared] + string(es.data[offset:end]), end
+}
+
+// Contains checks if an item is in the set
+func (es *ExactSet) Contains(item string) bool {
+	// Find the last block whose first item is not after item
+	block := sort.Search(len(es.blocks), func(i int) bool {
+		head, _ := es.next(int(es.blocks[i]), "")
+		return head > item
+	}) - 1
+	if block < 0 {
+		return false
+	}
+
+	offset, end := int(es.blocks[block]), len(es.data)
+	if block+1 < len(es.blocks) {
+		end = int(es.blocks[block+1])
+	}
+	prev := ""
+	for offset < end {
+		prev, offset = es.next(offset, prev)
+		if cmp := strings.Compare(prev, item); cmp >= 0 {
+			return cmp == 0
+		}
+	}
+	return false
+}
+
+// Items returns all items in sorted order
+func (es *ExactSet) Items() []string {
+	items := make([]string, 0, es.count)
+	prev := ""
+	for block, offset := 0, 0; offset < len(es.data); {
+		if block < len(es.blocks) && offset == int(es.blocks[block]) {
+			prev = ""
+			block++
+		}
+		prev, offset = es.next(offset, prev)
+		items = append(items, prev)
+	}
+	return items
+}
+
+// Size returns the number of items in the set
+func (es *ExactSet) Size() int {
+	return es.count
+}
+
+// MemoryBytes returns the size of the encoded items and block index
+func (es *ExactSet) MemoryBytes() int {
+	return len(es.data) + 4*len(es.blocks)
+}
+
+// MarshalBinary encodes the set as its item count followed by the
+// front-coded items, the block index is rebuilt on load
+func (es *ExactSet) MarshalBinary() ([]byte, error) {
+	buf := binary.AppendUvarint(nil, uint64(es.count))
+	return append(buf, es.data...), nil
+}
+
+// UnmarshalBinary restores a set encoded by MarshalBinary
+func (es *ExactSet) UnmarshalBinary(data []byte) error {
+	count, n := binary.Uvarint(data)
+	if n <= 0 {
+		return fmt.Errorf("invalid exact set data: bad item count")
+	}
+	data = data[n:]
+
+	blocks := make([]uint32, 0, min(count, uint64(len(data)))/exactBlockSize+1)
+	offset, prevLen := 0, uint64(0)
+	for i := uint64(0); i < count; i++ {
+		if i%exactBlockSize == 0 {
+			blocks = append(blocks, uint32(offset))
+			prevLen = 0
+		}
+		shared, n1 := binary.Uvarint(data[offset:])
+		if n1 <= 0 || shared > prevLen {
+			return fmt.Errorf("invalid exact set data: item %d is corrupt", i)
+		}
+		length, n2 := binary.Uvarint(data[offset+n1:])
+		if n2 <= 0 || uint64(len(data)-offset-n1-n2) < length {
+			return fmt.Errorf("invalid exact set data: item %d is truncated", i)
+		}
+		offset += n1 + n2 + int(length)
+		prevLen = shared + length
+	}
+	if offset != len(data) {
+		return fmt.Errorf("invalid exact set data: %d trailing bytes", len(data)-offset)
+	}
+
+	es.data = slices.Clone(data)
+	es.blocks = blocks
+	es.count = int(count)
+	return nil
+}
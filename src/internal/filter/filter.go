@@ -1,11 +1,25 @@
 package filter
 
+import "fmt"
+
 // Filter interface for different filtering implementations
 type Filter interface {
 	Add(item string) error
 	Contains(item string) bool
 	Size() int
 	Delete(item string) bool
+	Stats() Stats
+}
+
+// Stats reports how full a filter is and its estimated false positive rate
+type Stats struct {
+	Type              Type    `json:"type"`
+	Items             int     `json:"items"`
+	Capacity          int     `json:"capacity,omitempty"` // 0 for filters without a fixed capacity
+	Stages            int     `json:"stages,omitempty"`   // Sub-filters chained by a scalable filter
+	FillRatio         float64 `json:"fill_ratio"`
+	FalsePositiveRate float64 `json:"false_positive_rate"`
+	MemoryBytes       int     `json:"memory_bytes"` // Approximate for map filters
 }
 
 // ExtendedFilter interface for filters that support additional operations
@@ -14,3 +28,34 @@ type ExtendedFilter interface {
 	Clear()
 	GetAll() []string
 }
+
+// Type selects a filter implementation
+type Type string
+
+const (
+	TypeMap    Type = "map"    // Exact matching, no false positives
+	TypeBloom  Type = "bloom"  // Smallest footprint, no deletes
+	TypeCuckoo Type = "cuckoo" // Compact, supports deletes
+	TypeHybrid Type = "hybrid" // Bloom prefilter confirmed by an exact set, no false positives
+)
+
+// New creates a filter of the given type sized for capacity items at the
+// target false positive rate, which map filters ignore. Bloom and cuckoo
+// filters are scalable and keep accepting items past capacity.
+func New(filterType Type, capacity int, falsePositiveRate float64) (Filter, error) {
+	switch filterType {
+	case TypeMap:
+		return NewMapFilter(), nil
+	case TypeBloom, TypeCuckoo, TypeHybrid:
+		if falsePositiveRate <= 0 || falsePositiveRate >= 1 {
+			return nil, fmt.Errorf("%s filter requires a false positive rate between 0 and 1, got %v",
+				filterType, falsePositiveRate)
+		}
+		if filterType == TypeHybrid {
+			return NewHybridFilter(capacity, falsePositiveRate)
+		}
+		return NewScalableFilter(filterType, capacity, falsePositiveRate)
+	default:
+		return nil, fmt.Errorf("unknown filter type: %s", filterType)
+	}
+}
@@ -0,0 +1,235 @@
+package filter
+
+import (
+	"encoding"
+	"encoding/binary"
+	"fmt"
+	"testing"
+)
+
+// testItems returns n distinct domains starting at offset
+func testItems(offset, n int) []string {
+	items := make([]string, n)
+	for i := range items {
+		items[i] = fmt.Sprintf("domain-%d.example", offset+i)
+	}
+	return items
+}
+
+// newFilled creates a filter of filterType holding items
+func newFilled(t *testing.T, filterType Type, items []string, falsePositiveRate float64) Filter {
+	t.Helper()
+	f, err := New(filterType, len(items), falsePositiveRate)
+	if err != nil {
+		t.Fatalf("New(%s): %v", filterType, err)
+	}
+	for _, item := range items {
+		if err := f.Add(item); err != nil {
+			t.Fatalf("Add(%q): %v", item, err)
+		}
+	}
+	return f
+}
+
+// falsePositives counts the items of probes the filter contains
+func falsePositives(f Filter, probes []string) int {
+	count := 0
+	for _, probe := range probes {
+		if f.Contains(probe) {
+			count++
+		}
+	}
+	return count
+}
+
+var allTypes = []Type{TypeMap, TypeBloom, TypeCuckoo, TypeHybrid}
+
+func TestFilterNoFalseNegatives(t *testing.T) {
+	items := testItems(0, 5000)
+	for _, filterType := range allTypes {
+		t.Run(string(filterType), func(t *testing.T) {
+			f := newFilled(t, filterType, items, 0.01)
+			for _, item := range items {
+				if !f.Contains(item) {
+					t.Fatalf("Contains(%q) = false for an added item", item)
+				}
+			}
+			// Bloom filters count items that set a new bit, so they may
+			// take a few distinct items for duplicates
+			want, slack := len(items), 0
+			if filterType == TypeBloom {
+				slack = len(items) / 100
+			}
+			if size := f.Size(); size > want || size < want-slack {
+				t.Errorf("Size() = %d, want %d", size, want)
+			}
+		})
+	}
+}
+
+func TestFilterFalsePositiveRate(t *testing.T) {
+	items := testItems(0, 10000)
+	probes := testItems(len(items), 100000)
+
+	tests := []struct {
+		filterType Type
+		rate       float64
+		maxRate    float64 // Observed rate allowed, exact filters allow none
+	}{
+		{TypeBloom, 0.01, 0.02},
+		{TypeBloom, 0.001, 0.002},
+		{TypeCuckoo, 0.01, 0.02},
+		{TypeMap, 0.01, 0},
+		{TypeHybrid, 0.01, 0},
+		{TypeHybrid, 0.2, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s/%v", tt.filterType, tt.rate), func(t *testing.T) {
+			f := newFilled(t, tt.filterType, items, tt.rate)
+			got := float64(falsePositives(f, probes)) / float64(len(probes))
+			if got > tt.maxRate {
+				t.Errorf("false positive rate = %v, want at most %v", got, tt.maxRate)
+			}
+		})
+	}
+}
+
+func TestFilterPastCapacity(t *testing.T) {
+	// Scalable filters keep their rate when they hold more than planned
+	items := testItems(0, 20000)
+	probes := testItems(len(items), 50000)
+	for _, filterType := range []Type{TypeBloom, TypeCuckoo, TypeHybrid} {
+		t.Run(string(filterType), func(t *testing.T) {
+			f, err := New(filterType, 1000, 0.01)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, item := range items {
+				if err := f.Add(item); err != nil {
+					t.Fatalf("Add(%q): %v", item, err)
+				}
+			}
+			for _, item := range items {
+				if !f.Contains(item) {
+					t.Fatalf("Contains(%q) = false for an added item", item)
+				}
+			}
+			if got := float64(falsePositives(f, probes)) / float64(len(probes)); got > 0.03 {
+				t.Errorf("false positive rate = %v past capacity", got)
+			}
+		})
+	}
+}
+
+func TestFilterBinaryRoundTrip(t *testing.T) {
+	items := testItems(0, 3000)
+	probes := testItems(len(items), 20000)
+	for _, filterType := range allTypes {
+		t.Run(string(filterType), func(t *testing.T) {
+			f := newFilled(t, filterType, items, 0.01)
+			data, err := f.(encoding.BinaryMarshaler).MarshalBinary()
+			if err != nil {
+				t.Fatalf("MarshalBinary: %v", err)
+			}
+
+			restored, err := New(filterType, 1, 0.5)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := restored.(encoding.BinaryUnmarshaler).UnmarshalBinary(data); err != nil {
+				t.Fatalf("UnmarshalBinary: %v", err)
+			}
+
+			if restored.Size() != f.Size() {
+				t.Errorf("Size() = %d after round trip, want %d", restored.Size(), f.Size())
+			}
+			for _, item := range items {
+				if !restored.Contains(item) {
+					t.Fatalf("Contains(%q) = false after round trip", item)
+				}
+			}
+			// The restored filter answers exactly like the original
+			for _, probe := range probes {
+				if restored.Contains(probe) != f.Contains(probe) {
+					t.Fatalf("Contains(%q) differs after round trip", probe)
+				}
+			}
+		})
+	}
+}
+
+func TestFilterUnmarshalCorrupt(t *testing.T) {
+	for _, filterType := range allTypes {
+		t.Run(string(filterType), func(t *testing.T) {
+			f := newFilled(t, filterType, testItems(0, 100), 0.01)
+			data, err := f.(encoding.BinaryMarshaler).MarshalBinary()
+			if err != nil {
+				t.Fatal(err)
+			}
+			restored, _ := New(filterType, 1, 0.5)
+			if err := restored.(encoding.BinaryUnmarshaler).UnmarshalBinary(data[:len(data)/2]); err == nil {
+				t.Error("UnmarshalBinary accepted truncated data")
+			}
+		})
+	}
+}
+
+func TestBloomUnmarshalCraftedHeader(t *testing.T) {
+	header := func(numBits, numHashes uint64, words int) []byte {
+		data := make([]byte, 32+8*words)
+		binary.LittleEndian.PutUint64(data[0:], numBits)
+		binary.LittleEndian.PutUint64(data[8:], numHashes)
+		return data
+	}
+
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		// (numBits+63)/64*8 wraps to 0 and would match an empty bit array
+		{"bit count overflow", header(^uint64(0)-62, 3, 0)},
+		{"bit count overflow with words", header(^uint64(0), 3, 1)},
+		{"no bits", header(0, 3, 0)},
+		{"no hashes", header(64, 0, 1)},
+		{"too many hashes", header(64, maxBloomHashes+1, 1)},
+		{"hash count overflow", header(64, ^uint64(0), 1)},
+		{"too few words", header(129, 3, 2)},
+		{"too many words", header(64, 3, 2)},
+		{"partial word", append(header(64, 3, 1), 0)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bf := NewBloomFilter(1, 0.5)
+			if err := bf.UnmarshalBinary(tt.data); err == nil {
+				t.Error("UnmarshalBinary accepted a crafted header")
+			}
+		})
+	}
+
+	// Bit counts that fill the last word partly are valid, and so is the
+	// largest hash count
+	if err := NewBloomFilter(1, 0.5).UnmarshalBinary(header(65, 3, 2)); err != nil {
+		t.Errorf("UnmarshalBinary: %v", err)
+	}
+	if err := NewBloomFilter(1, 0.5).UnmarshalBinary(header(64, maxBloomHashes, 1)); err != nil {
+		t.Errorf("UnmarshalBinary: %v", err)
+	}
+}
+
+func TestNewInvalid(t *testing.T) {
+	tests := []struct {
+		filterType Type
+		rate       float64
+	}{
+		{TypeBloom, 0},
+		{TypeCuckoo, 1},
+		{TypeHybrid, -0.1},
+		{"unknown", 0.01},
+	}
+	for _, tt := range tests {
+		if _, err := New(tt.filterType, 100, tt.rate); err == nil {
+			t.Errorf("New(%s, %v) succeeded, want an error", tt.filterType, tt.rate)
+		}
+	}
+}
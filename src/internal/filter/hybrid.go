@@ -0,0 +1,194 @@
+package filter
+
+import (
+	"encoding/binary"
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+)
+
+// hybridMinPending is the number of added items buffered before they are
+// merged into the exact set, the buffer also grows with the set so merges
+// stay amortized
+const hybridMinPending = 1024
+
+// HybridFilter implements Filter with a scalable Bloom prefilter that
+// answers most negatives quickly, and an exact set that confirms its
+// positives so the filter never gives false positives
+type HybridFilter struct {
+	prefilter *ScalableFilter
+	exact     *ExactSet
+	pending   map[string]bool // Added items not merged into exact yet
+	mu        sync.RWMutex
+}
+
+// NewHybridFilter creates a new hybrid filter whose prefilter holds
+// capacity items at the given false positive rate
+func NewHybridFilter(capacity int, falsePositiveRate float64) (*HybridFilter, error) {
+	prefilter, err := NewScalableFilter(TypeBloom, capacity, falsePositiveRate)
+	if err != nil {
+		return nil, err
+	}
+
+	return &HybridFilter{
+		prefilter: prefilter,
+		exact:     NewExactSet(nil),
+		pending:   make(map[string]bool),
+	}, nil
+}
+
+// merge rebuilds the exact set with the pending items
+func (hf *HybridFilter) merge() {
+	if len(hf.pending) == 0 {
+		return
+	}
+	items := append(hf.exact.Items(), slices.Collect(maps.Keys(hf.pending))...)
+	hf.exact = NewExactSet(items)
+	clear(hf.pending)
+}
+
+// Add adds an item to the filter
+func (hf *HybridFilter) Add(item string) error {
+	hf.mu.Lock()
+	defer hf.mu.Unlock()
+
+	if err := hf.prefilter.Add(item); err != nil {
+		return err
+	}
+	hf.pending[item] = true
+	if len(hf.pending) >= max(hybridMinPending, hf.exact.Size()/4) {
+		hf.merge()
+	}
+	return nil
+}
+
+// Contains checks if an item exists in the filter
+func (hf *HybridFilter) Contains(item string) bool {
+	hf.mu.RLock()
+	defer hf.mu.RUnlock()
+
+	if !hf.prefilter.Contains(item) {
+		return false
+	}
+	return hf.pending[item] || hf.exact.Contains(item)
+}
+
+// Size returns the number of items in the filter
+func (hf *HybridFilter) Size() int {
+	hf.mu.RLock()
+	defer hf.mu.RUnlock()
+
+	return hf.size()
+}
+
+// size returns the number of items, the caller must hold the lock
+func (hf *HybridFilter) size() int {
+	// Pending items may already be in the exact set
+	size := hf.exact.Size()
+	for item := range hf.pending {
+		if !hf.exact.Contains(item) {
+			size++
+		}
+	}
+	return size
+}
+
+// Delete removes an item from the exact set, the Bloom prefilter keeps it
+// but its positives are no longer confirmed
+func (hf *HybridFilter) Delete(item string) bool {
+	hf.mu.Lock()
+	defer hf.mu.Unlock()
+
+	hf.merge()
+	if !hf.exact.Contains(item) {
+		return false
+	}
+	hf.exact = NewExactSet(slices.DeleteFunc(hf.exact.Items(), func(s string) bool {
+		return s == item
+	}))
+	return true
+}
+
+// Clear removes all items from the filter
+func (hf *HybridFilter) Clear() {
+	hf.mu.Lock()
+	defer hf.mu.Unlock()
+
+	hf.prefilter.Clear()
+	hf.exact = NewExactSet(nil)
+	clear(hf.pending)
+}
+
+// GetAll returns all items in the filter
+func (hf *HybridFilter) GetAll() []string {
+	hf.mu.Lock()
+	defer hf.mu.Unlock()
+
+	hf.merge()
+	return hf.exact.Items()
+}
+
+// Stats reports the prefilter's fill ratio, the memory of both tiers and
+// no false positives
+func (hf *HybridFilter) Stats() Stats {
+	hf.mu.RLock()
+	defer hf.mu.RUnlock()
+
+	stats := hf.prefilter.Stats()
+	stats.Type = TypeHybrid
+	stats.Items = hf.size()
+	stats.FalsePositiveRate = 0
+	stats.MemoryBytes += hf.exact.MemoryBytes() + mapMemoryBytes(hf.pending)
+	return stats
+}
+
+// MarshalBinary encodes the filter as the length-prefixed prefilter
+// followed by the exact set
+func (hf *HybridFilter) MarshalBinary() ([]byte, error) {
+	hf.mu.Lock()
+	defer hf.mu.Unlock()
+
+	hf.merge()
+	prefilter, err := hf.prefilter.MarshalBinary()
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode prefilter: %w", err)
+	}
+	exact, err := hf.exact.MarshalBinary()
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode exact set: %w", err)
+	}
+
+	buf := binary.LittleEndian.AppendUint64(nil, uint64(len(prefilter)))
+	buf = append(buf, prefilter...)
+	return append(buf, exact...), nil
+}
+
+// UnmarshalBinary restores a filter encoded by MarshalBinary
+func (hf *HybridFilter) UnmarshalBinary(data []byte) error {
+	if len(data) < 8 {
+		return fmt.Errorf("hybrid filter data too short: %d bytes", len(data))
+	}
+	length := binary.LittleEndian.Uint64(data)
+	data = data[8:]
+	if uint64(len(data)) < length {
+		return fmt.Errorf("invalid hybrid filter data: prefilter is truncated")
+	}
+
+	prefilter := &ScalableFilter{}
+	if err := prefilter.UnmarshalBinary(data[:length]); err != nil {
+		return fmt.Errorf("failed to decode prefilter: %w", err)
+	}
+	exact := &ExactSet{}
+	if err := exact.UnmarshalBinary(data[length:]); err != nil {
+		return fmt.Errorf("failed to decode exact set: %w", err)
+	}
+
+	hf.mu.Lock()
+	defer hf.mu.Unlock()
+
+	hf.prefilter = prefilter
+	hf.exact = exact
+	hf.pending = make(map[string]bool)
+	return nil
+}
@@ -0,0 +1,112 @@
+package filter
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestExactSet(t *testing.T) {
+	items := []string{"mail.ru", "gmail.com", "googlemail.com", "gmail.com", "gmx.de", "gmx.net", "a", ""}
+	es := NewExactSet(items)
+
+	for _, item := range items {
+		if !es.Contains(item) {
+			t.Errorf("Contains(%q) = false", item)
+		}
+	}
+	for _, item := range []string{"gmail.co", "gmail.comm", "gmx", "b", "zzz"} {
+		if es.Contains(item) {
+			t.Errorf("Contains(%q) = true for an item not in the set", item)
+		}
+	}
+
+	want := []string{"", "a", "gmail.com", "gmx.de", "gmx.net", "googlemail.com", "mail.ru"}
+	if got := es.Items(); !slices.Equal(got, want) {
+		t.Errorf("Items() = %v, want %v", got, want)
+	}
+	if es.Size() != len(want) {
+		t.Errorf("Size() = %d, want %d", es.Size(), len(want))
+	}
+}
+
+func TestExactSetLarge(t *testing.T) {
+	// Enough items for many front-coded blocks
+	items := testItems(0, 10*exactBlockSize+3)
+	es := NewExactSet(items)
+	for _, item := range items {
+		if !es.Contains(item) {
+			t.Fatalf("Contains(%q) = false", item)
+		}
+	}
+	if falsePositives(filterFunc(es.Contains), testItems(len(items), 1000)) != 0 {
+		t.Error("exact set contains items that were never added")
+	}
+}
+
+func TestHybridFilterNoFalsePositives(t *testing.T) {
+	// A prefilter this loose answers yes to most probes, so every probe
+	// reaching the exact set is checked there
+	items := testItems(0, 3*hybridMinPending+17)
+	probes := testItems(len(items), 50000)
+
+	f, err := NewHybridFilter(100, 0.5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, item := range items {
+		if err := f.Add(item); err != nil {
+			t.Fatalf("Add(%q): %v", item, err)
+		}
+		// Pending items are found before they are merged
+		if !f.Contains(items[i/2]) {
+			t.Fatalf("Contains(%q) = false after %d adds", items[i/2], i+1)
+		}
+	}
+
+	if n := falsePositives(f, probes); n != 0 {
+		t.Errorf("%d false positives, want none", n)
+	}
+	if f.Size() != len(items) {
+		t.Errorf("Size() = %d, want %d", f.Size(), len(items))
+	}
+	if stats := f.Stats(); stats.FalsePositiveRate != 0 || stats.Type != TypeHybrid {
+		t.Errorf("Stats() = %+v, want a hybrid filter without false positives", stats)
+	}
+}
+
+func TestHybridFilterDelete(t *testing.T) {
+	items := testItems(0, 100)
+	f, err := NewHybridFilter(len(items), 0.01)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, item := range items {
+		f.Add(item)
+	}
+
+	if !f.Delete(items[10]) {
+		t.Fatalf("Delete(%q) = false", items[10])
+	}
+	if f.Contains(items[10]) {
+		t.Errorf("Contains(%q) = true after Delete", items[10])
+	}
+	if f.Delete(items[10]) {
+		t.Errorf("second Delete(%q) = true", items[10])
+	}
+	if !f.Contains(items[11]) {
+		t.Errorf("Contains(%q) = false after deleting another item", items[11])
+	}
+	if f.Size() != len(items)-1 {
+		t.Errorf("Size() = %d, want %d", f.Size(), len(items)-1)
+	}
+}
+
+// filterFunc adapts a membership test to the parts of Filter that
+// falsePositives uses
+type filterFunc func(string) bool
+
+func (fn filterFunc) Add(string) error          { return nil }
+func (fn filterFunc) Contains(item string) bool { return fn(item) }
+func (fn filterFunc) Size() int                 { return 0 }
+func (fn filterFunc) Delete(string) bool        { return false }
+func (fn filterFunc) Stats() Stats              { return Stats{} }
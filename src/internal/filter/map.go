@@ -1,6 +1,8 @@
 package filter
 
 import (
+	"encoding/binary"
+	"fmt"
 	"sync"
 )
 
@@ -42,6 +44,32 @@ func (mf *MapFilter) Size() int {
 	return len(mf.items)
 }
 
+// mapEntryOverhead approximates the bytes a map entry takes besides its key
+// data: the string header, the value and the bucket overhead
+const mapEntryOverhead = 40
+
+// mapMemoryBytes approximates the memory held by a set of strings in a map
+func mapMemoryBytes(items map[string]bool) int {
+	size := len(items) * mapEntryOverhead
+	for item := range items {
+		size += len(item)
+	}
+	return size
+}
+
+// Stats reports the number of items and their approximate memory, map
+// filters never give false positives
+func (mf *MapFilter) Stats() Stats {
+	mf.mu.RLock()
+	defer mf.mu.RUnlock()
+
+	return Stats{
+		Type:        TypeMap,
+		Items:       len(mf.items),
+		MemoryBytes: mapMemoryBytes(mf.items),
+	}
+}
+
 // Delete removes an item from the filter
 func (mf *MapFilter) Delete(item string) bool {
 	mf.mu.Lock()
@@ -73,3 +101,45 @@ func (mf *MapFilter) GetAll() []string {
 	}
 	return items
 }
+
+// MarshalBinary encodes the filter as a count followed by length-prefixed
+// items
+func (mf *MapFilter) MarshalBinary() ([]byte, error) {
+	mf.mu.RLock()
+	defer mf.mu.RUnlock()
+
+	buf := binary.AppendUvarint(nil, uint64(len(mf.items)))
+	for item := range mf.items {
+		buf = binary.AppendUvarint(buf, uint64(len(item)))
+		buf = append(buf, item...)
+	}
+	return buf, nil
+}
+
+// UnmarshalBinary restores a filter encoded by MarshalBinary
+func (mf *MapFilter) UnmarshalBinary(data []byte) error {
+	count, n := binary.Uvarint(data)
+	if n <= 0 {
+		return fmt.Errorf("invalid map filter data: bad item count")
+	}
+	data = data[n:]
+
+	items := make(map[string]bool, min(count, uint64(len(data))))
+	for i := uint64(0); i < count; i++ {
+		length, n := binary.Uvarint(data)
+		if n <= 0 || uint64(len(data)-n) < length {
+			return fmt.Errorf("invalid map filter data: item %d is truncated", i)
+		}
+		items[string(data[n:n+int(length)])] = true
+		data = data[n+int(length):]
+	}
+	if len(data) != 0 {
+		return fmt.Errorf("invalid map filter data: %d trailing bytes", len(data))
+	}
+
+	mf.mu.Lock()
+	defer mf.mu.Unlock()
+
+	mf.items = items
+	return nil
+}
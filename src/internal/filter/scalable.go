@@ -0,0 +1,252 @@
+package filter
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"sync"
+)
+
+const (
+	scalableGrowth     = 2   // Each stage holds twice the items of the previous one
+	scalableTightening = 0.5 // Each stage has half the false positive rate of the previous one
+)
+
+// ScalableFilter implements Filter by chaining Bloom or cuckoo filters,
+// adding a larger stage whenever the newest one fills up so adds never
+// fail. Stage false positive rates shrink geometrically and sum to at most
+// the target rate, as in scalable Bloom filters (Almeida et al., 2007).
+type ScalableFilter struct {
+	stageType         Type
+	falsePositiveRate float64
+	stages            []scalableStage
+	mu                sync.RWMutex
+}
+
+// scalableStage is one filter in the chain
+type scalableStage struct {
+	filter            snapshotFilter
+	capacity          int
+	falsePositiveRate float64
+}
+
+// NewScalableFilter creates a scalable filter of Bloom or cuckoo stages
+// whose first stage holds capacity items, with an overall false positive
+// rate of at most falsePositiveRate
+func NewScalableFilter(stageType Type, capacity int, falsePositiveRate float64) (*ScalableFilter, error) {
+	if stageType != TypeBloom && stageType != TypeCuckoo {
+		return nil, fmt.Errorf("scalable filters chain bloom or cuckoo filters, got %s", stageType)
+	}
+
+	sf := &ScalableFilter{
+		stageType:         stageType,
+		falsePositiveRate: falsePositiveRate,
+	}
+	sf.addStage(max(capacity, 1), falsePositiveRate*(1-scalableTightening))
+	return sf, nil
+}
+
+// addStage appends an empty stage to the chain
+func (sf *ScalableFilter) addStage(capacity int, falsePositiveRate float64) {
+	var f snapshotFilter
+	if sf.stageType == TypeBloom {
+		f = NewBloomFilter(capacity, falsePositiveRate)
+	} else {
+		f = NewCuckooFilter(capacity, falsePositiveRate)
+	}
+
+	sf.stages = append(sf.stages, scalableStage{
+		filter:            f,
+		capacity:          capacity,
+		falsePositiveRate: falsePositiveRate,
+	})
+}
+
+// grow appends a stage following the newest one
+func (sf *ScalableFilter) grow() *scalableStage {
+	last := sf.stages[len(sf.stages)-1]
+	sf.addStage(last.capacity*scalableGrowth, last.falsePositiveRate*scalableTightening)
+	return &sf.stages[len(sf.stages)-1]
+}
+
+// Add adds an item to the newest stage, growing the chain when it is full
+func (sf *ScalableFilter) Add(item string) error {
+	sf.mu.Lock()
+	defer sf.mu.Unlock()
+
+	stage := &sf.stages[len(sf.stages)-1]
+	if stage.filter.Size() >= stage.capacity {
+		stage = sf.grow()
+	}
+	if err := stage.filter.Add(item); err == nil {
+		return nil
+	}
+
+	// Cuckoo stages can fill up before reaching their capacity
+	return sf.grow().filter.Add(item)
+}
+
+// Contains checks if an item may exist in any stage
+func (sf *ScalableFilter) Contains(item string) bool {
+	sf.mu.RLock()
+	defer sf.mu.RUnlock()
+
+	for _, stage := range sf.stages {
+		if stage.filter.Contains(item) {
+			return true
+		}
+	}
+	return false
+}
+
+// Size returns the number of items across all stages
+func (sf *ScalableFilter) Size() int {
+	sf.mu.RLock()
+	defer sf.mu.RUnlock()
+
+	size := 0
+	for _, stage := range sf.stages {
+		size += stage.filter.Size()
+	}
+	return size
+}
+
+// Delete removes an item from the newest stage holding it, only cuckoo
+// stages support deletes
+func (sf *ScalableFilter) Delete(item string) bool {
+	sf.mu.Lock()
+	defer sf.mu.Unlock()
+
+	for i := len(sf.stages) - 1; i >= 0; i-- {
+		if sf.stages[i].filter.Delete(item) {
+			return true
+		}
+	}
+	return false
+}
+
+// Clear removes all items and shrinks the chain back to its first stage
+func (sf *ScalableFilter) Clear() {
+	sf.mu.Lock()
+	defer sf.mu.Unlock()
+
+	first := sf.stages[0]
+	sf.stages = nil
+	sf.addStage(first.capacity, first.falsePositiveRate)
+}
+
+// GetAll returns all items in the filter
+// Note: Bloom and cuckoo filters don't support enumeration, so this returns an empty slice
+func (sf *ScalableFilter) GetAll() []string {
+	return []string{}
+}
+
+// Stats reports the share of the chain's capacity in use and the combined
+// false positive rate of its stages, 1 - Π(1 - p_i)
+func (sf *ScalableFilter) Stats() Stats {
+	sf.mu.RLock()
+	defer sf.mu.RUnlock()
+
+	stats := Stats{
+		Type:   sf.stageType,
+		Stages: len(sf.stages),
+	}
+
+	miss := 1.0
+	for _, stage := range sf.stages {
+		stageStats := stage.filter.Stats()
+		stats.Items += stageStats.Items
+		stats.Capacity += stage.capacity
+		stats.MemoryBytes += stageStats.MemoryBytes
+		miss *= 1 - stageStats.FalsePositiveRate
+	}
+	stats.FillRatio = float64(stats.Items) / float64(stats.Capacity)
+	stats.FalsePositiveRate = 1 - miss
+
+	return stats
+}
+
+// MarshalBinary encodes the filter as its parameters followed by each
+// stage's capacity, false positive rate and length-prefixed encoding
+func (sf *ScalableFilter) MarshalBinary() ([]byte, error) {
+	sf.mu.RLock()
+	defer sf.mu.RUnlock()
+
+	buf := []byte{byte(len(sf.stageType))}
+	buf = append(buf, sf.stageType...)
+	buf = binary.LittleEndian.AppendUint64(buf, math.Float64bits(sf.falsePositiveRate))
+	buf = binary.LittleEndian.AppendUint32(buf, uint32(len(sf.stages)))
+
+	for i, stage := range sf.stages {
+		data, err := stage.filter.MarshalBinary()
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode stage %d: %w", i, err)
+		}
+		buf = binary.LittleEndian.AppendUint64(buf, uint64(stage.capacity))
+		buf = binary.LittleEndian.AppendUint64(buf, math.Float64bits(stage.falsePositiveRate))
+		buf = binary.LittleEndian.AppendUint64(buf, uint64(len(data)))
+		buf = append(buf, data...)
+	}
+	return buf, nil
+}
+
+// UnmarshalBinary restores a filter encoded by MarshalBinary
+func (sf *ScalableFilter) UnmarshalBinary(data []byte) error {
+	if len(data) < 1 || len(data) < 1+int(data[0])+12 {
+		return fmt.Errorf("scalable filter data too short: %d bytes", len(data))
+	}
+	stageType := Type(data[1 : 1+data[0]])
+	if stageType != TypeBloom && stageType != TypeCuckoo {
+		return fmt.Errorf("invalid scalable filter stage type: %s", stageType)
+	}
+	data = data[1+len(stageType):]
+	falsePositiveRate := math.Float64frombits(binary.LittleEndian.Uint64(data))
+	count := binary.LittleEndian.Uint32(data[8:])
+	data = data[12:]
+
+	if count == 0 {
+		return fmt.Errorf("invalid scalable filter data: no stages")
+	}
+
+	stages := make([]scalableStage, 0, min(int(count), len(data)/24))
+	for i := 0; i < int(count); i++ {
+		if len(data) < 24 {
+			return fmt.Errorf("invalid scalable filter data: stage %d is truncated", i)
+		}
+		capacity := binary.LittleEndian.Uint64(data)
+		stageRate := math.Float64frombits(binary.LittleEndian.Uint64(data[8:]))
+		length := binary.LittleEndian.Uint64(data[16:])
+		data = data[24:]
+		if uint64(len(data)) < length {
+			return fmt.Errorf("invalid scalable filter data: stage %d is truncated", i)
+		}
+
+		var f snapshotFilter
+		if stageType == TypeBloom {
+			f = &BloomFilter{}
+		} else {
+			f = &CuckooFilter{}
+		}
+		if err := f.UnmarshalBinary(data[:length]); err != nil {
+			return fmt.Errorf("failed to decode stage %d: %w", i, err)
+		}
+		data = data[length:]
+
+		stages = append(stages, scalableStage{
+			filter:            f,
+			capacity:          int(capacity),
+			falsePositiveRate: stageRate,
+		})
+	}
+	if len(data) != 0 {
+		return fmt.Errorf("invalid scalable filter data: %d trailing bytes", len(data))
+	}
+
+	sf.mu.Lock()
+	defer sf.mu.Unlock()
+
+	sf.stageType = stageType
+	sf.falsePositiveRate = falsePositiveRate
+	sf.stages = stages
+	return nil
+}
@@ -0,0 +1,151 @@
+package filter
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+)
+
+// SnapshotVersion is the snapshot file format version written by
+// WriteSnapshot. Snapshots with any other version are rejected. Version 3
+// records the entries read and rejected, and replaces snapshots built from
+// unnormalized lists.
+const SnapshotVersion = 3
+
+// snapshotMagic identifies a filter snapshot file
+var snapshotMagic = [4]byte{'B', 'B', 'F', 'S'}
+
+// SnapshotHeader describes the filter stored in a snapshot and the source
+// list it was built from
+type SnapshotHeader struct {
+	Version           uint16
+	Type              Type
+	FalsePositiveRate float64
+	Count             uint64   // Items kept from the source list
+	Entries           uint64   // Entries read from the source list
+	Rejected          uint64   // Entries rejected while loading the source list
+	Checksum          [32]byte // SHA-256 of the source list
+}
+
+// snapshotFilter is a filter that can be stored in a snapshot
+type snapshotFilter interface {
+	Filter
+	encoding.BinaryMarshaler
+	encoding.BinaryUnmarshaler
+}
+
+// typeOf returns the type of a filter created by New
+func typeOf(f Filter) (Type, error) {
+	switch f := f.(type) {
+	case *MapFilter:
+		return TypeMap, nil
+	case *HybridFilter:
+		return TypeHybrid, nil
+	case *ScalableFilter:
+		f.mu.RLock()
+		defer f.mu.RUnlock()
+		return f.stageType, nil
+	default:
+		return "", fmt.Errorf("filter %T cannot be snapshotted", f)
+	}
+}
+
+// WriteSnapshot stores f and its header in filename, replacing any
+// existing snapshot atomically. The header version and type are taken
+// from the filter.
+func WriteSnapshot(filename string, header SnapshotHeader, f Filter) error {
+	filterType, err := typeOf(f)
+	if err != nil {
+		return err
+	}
+	payload, err := f.(snapshotFilter).MarshalBinary()
+	if err != nil {
+		return fmt.Errorf("failed to encode %s filter: %w", filterType, err)
+	}
+
+	var buf bytes.Buffer
+	buf.Write(snapshotMagic[:])
+	binary.Write(&buf, binary.LittleEndian, uint16(SnapshotVersion))
+	buf.WriteByte(byte(len(filterType)))
+	buf.WriteString(string(filterType))
+	binary.Write(&buf, binary.LittleEndian, math.Float64bits(header.FalsePositiveRate))
+	binary.Write(&buf, binary.LittleEndian, header.Count)
+	binary.Write(&buf, binary.LittleEndian, header.Entries)
+	binary.Write(&buf, binary.LittleEndian, header.Rejected)
+	buf.Write(header.Checksum[:])
+	buf.Write(payload)
+
+	// Write to a temporary file first so readers never see a partial snapshot
+	tmp, err := os.CreateTemp(filepath.Dir(filename), filepath.Base(filename)+".tmp*")
+	if err != nil {
+		return fmt.Errorf("failed to create snapshot file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(buf.Bytes()); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write snapshot file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write snapshot file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), filename); err != nil {
+		return fmt.Errorf("failed to replace snapshot file: %w", err)
+	}
+	return nil
+}
+
+// ReadSnapshot loads the header and filter stored in filename
+func ReadSnapshot(filename string) (SnapshotHeader, Filter, error) {
+	var header SnapshotHeader
+
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return header, nil, fmt.Errorf("failed to read snapshot file: %w", err)
+	}
+
+	if len(data) < len(snapshotMagic)+3 || !bytes.Equal(data[:len(snapshotMagic)], snapshotMagic[:]) {
+		return header, nil, fmt.Errorf("%s is not a filter snapshot", filename)
+	}
+	data = data[len(snapshotMagic):]
+
+	header.Version = binary.LittleEndian.Uint16(data)
+	if header.Version != SnapshotVersion {
+		return header, nil, fmt.Errorf("unsupported snapshot version %d", header.Version)
+	}
+
+	typeLen := int(data[2])
+	data = data[3:]
+	if len(data) < typeLen+8+8+8+8+len(header.Checksum) {
+		return header, nil, fmt.Errorf("snapshot header is truncated")
+	}
+	header.Type = Type(data[:typeLen])
+	data = data[typeLen:]
+	header.FalsePositiveRate = math.Float64frombits(binary.LittleEndian.Uint64(data))
+	header.Count = binary.LittleEndian.Uint64(data[8:])
+	header.Entries = binary.LittleEndian.Uint64(data[16:])
+	header.Rejected = binary.LittleEndian.Uint64(data[24:])
+	copy(header.Checksum[:], data[32:])
+	data = data[32+len(header.Checksum):]
+
+	var f snapshotFilter
+	switch header.Type {
+	case TypeMap:
+		f = &MapFilter{}
+	case TypeBloom, TypeCuckoo:
+		f = &ScalableFilter{}
+	case TypeHybrid:
+		f = &HybridFilter{}
+	default:
+		return header, nil, fmt.Errorf("unknown filter type in snapshot: %s", header.Type)
+	}
+	if err := f.UnmarshalBinary(data); err != nil {
+		return header, nil, fmt.Errorf("failed to decode %s filter: %w", header.Type, err)
+	}
+
+	return header, f, nil
+}
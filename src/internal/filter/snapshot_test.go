@@ -0,0 +1,116 @@
+package filter
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSnapshotRoundTrip(t *testing.T) {
+	items := testItems(0, 2000)
+	probes := testItems(len(items), 10000)
+
+	for _, filterType := range allTypes {
+		t.Run(string(filterType), func(t *testing.T) {
+			f := newFilled(t, filterType, items, 0.01)
+			filename := filepath.Join(t.TempDir(), "list.snapshot")
+			header := SnapshotHeader{
+				FalsePositiveRate: 0.01,
+				Count:             uint64(len(items)),
+				Entries:           uint64(len(items) + 5),
+				Rejected:          3,
+				Checksum:          sha256.Sum256([]byte("list")),
+			}
+			if err := WriteSnapshot(filename, header, f); err != nil {
+				t.Fatalf("WriteSnapshot: %v", err)
+			}
+
+			got, restored, err := ReadSnapshot(filename)
+			if err != nil {
+				t.Fatalf("ReadSnapshot: %v", err)
+			}
+			header.Version, header.Type = SnapshotVersion, filterType
+			if got != header {
+				t.Errorf("header = %+v, want %+v", got, header)
+			}
+			for _, item := range items {
+				if !restored.Contains(item) {
+					t.Fatalf("Contains(%q) = false after restoring", item)
+				}
+			}
+			for _, probe := range probes {
+				if restored.Contains(probe) != f.Contains(probe) {
+					t.Fatalf("Contains(%q) differs after restoring", probe)
+				}
+			}
+		})
+	}
+}
+
+func TestSnapshotReplacesExisting(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "list.snapshot")
+	for i, n := range []int{10, 20} {
+		f := newFilled(t, TypeMap, testItems(0, n), 0.01)
+		if err := WriteSnapshot(filename, SnapshotHeader{Count: uint64(n)}, f); err != nil {
+			t.Fatalf("WriteSnapshot %d: %v", i, err)
+		}
+	}
+
+	header, f, err := ReadSnapshot(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if header.Count != 20 || f.Size() != 20 {
+		t.Errorf("snapshot holds %d items, count %d, want the second write", f.Size(), header.Count)
+	}
+	// Temporary files are cleaned up
+	entries, err := os.ReadDir(filepath.Dir(filename))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("%d files in the snapshot directory, want 1", len(entries))
+	}
+}
+
+func TestReadSnapshotInvalid(t *testing.T) {
+	dir := t.TempDir()
+	valid := filepath.Join(dir, "valid.snapshot")
+	if err := WriteSnapshot(valid, SnapshotHeader{}, newFilled(t, TypeBloom, testItems(0, 100), 0.01)); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(valid)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	oldVersion := append([]byte(nil), data...)
+	binary.LittleEndian.PutUint16(oldVersion[len(snapshotMagic):], SnapshotVersion-1)
+
+	tests := []struct {
+		name    string
+		data    []byte
+		wantErr string
+	}{
+		{"not a snapshot", []byte("example.com\n"), "not a filter snapshot"},
+		{"old version", oldVersion, "unsupported snapshot version"},
+		{"truncated header", data[:len(snapshotMagic)+10], "truncated"},
+		{"truncated filter", data[:len(data)-8], "failed to decode"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filename := filepath.Join(dir, strings.ReplaceAll(tt.name, " ", "-"))
+			if err := os.WriteFile(filename, tt.data, 0o644); err != nil {
+				t.Fatal(err)
+			}
+			_, _, err := ReadSnapshot(filename)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ReadSnapshot error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,259 @@
+package loader
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// ListEntry is an item of a list file with the metadata the CSV and JSONL
+// list formats can carry
+type ListEntry struct {
+	Line     int // Line of the entry in its file
+	Value    string
+	Category string // Kind of entry, e.g. disposable, forwarding or relay
+	Source   string // Where the entry came from
+	Added    time.Time
+	Expires  time.Time // Zero when the entry doesn't expire
+	Reason   string    // Free-text explanation of why the entry is listed
+	Err      error     // Why the entry couldn't be read, NormalizeEntries rejects it
+}
+
+// Expired reports whether the entry has expired at now
+func (e ListEntry) Expired(now time.Time) bool {
+	return !e.Expires.IsZero() && !now.Before(e.Expires)
+}
+
+// entryFields is the JSONL form of a list entry, also used for CSV columns
+type entryFields struct {
+	Value    string `json:"value"`
+	Category string `json:"category"`
+	Source   string `json:"source"`
+	Added    string `json:"added"`
+	Expires  string `json:"expires"`
+	Reason   string `json:"reason"`
+}
+
+// IsRichList reports whether a list file carries entry metadata, which is
+// chosen by a .csv or .jsonl extension
+func IsRichList(filename string) bool {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".csv", ".jsonl":
+		return true
+	}
+	return false
+}
+
+// LoadEntries loads the raw entries of a list file, see NormalizeEntries
+// for cleaning them up. Plain text files give entries without metadata.
+// CSV files start with a header naming their columns, value plus any of
+// category, source, added, expires and reason, and JSONL files hold one
+// object per line with the same fields. Dates are YYYY-MM-DD or RFC 3339.
+// Rows that can't be read are returned with Err set, so they are counted
+// against the file's error rate rather than failing the whole load.
+func LoadEntries(filename string) ([]ListEntry, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file %s: %w", filename, err)
+	}
+	defer file.Close()
+
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".csv":
+		return readCSVEntries(filename, file)
+	case ".jsonl":
+		return readJSONLEntries(filename, file)
+	default:
+		return readTextEntries(filename, file)
+	}
+}
+
+// readTextEntries reads plain list entries, one per line with # comments
+func readTextEntries(filename string, r io.Reader) ([]ListEntry, error) {
+	var entries []ListEntry
+	scanner := bufio.NewScanner(r)
+
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+
+		// Remove comments, whole-line and inline
+		if commentIndex := strings.Index(line, "#"); commentIndex != -1 {
+			line = strings.TrimSpace(line[:commentIndex])
+		}
+
+		if line != "" {
+			entries = append(entries, ListEntry{Line: lineNum, Value: line})
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading file %s: %w", filename, err)
+	}
+
+	return entries, nil
+}
+
+// readCSVEntries reads CSV list entries, columns are matched to entry
+// fields by the header
+func readCSVEntries(filename string, r io.Reader) ([]ListEntry, error) {
+	reader := csv.NewReader(r)
+	reader.Comment = '#'
+	reader.TrimLeadingSpace = true
+	reader.FieldsPerRecord = -1 // Trailing empty columns may be left out
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read header of %s: %w", filename, err)
+	}
+	columns := make([]string, len(header))
+	hasValue := false
+	for i, column := range header {
+		column = strings.ToLower(strings.TrimSpace(column))
+		switch column {
+		case "value":
+			hasValue = true
+		case "category", "source", "added", "expires", "reason":
+		default:
+			return nil, fmt.Errorf("%s: unknown column %q", filename, column)
+		}
+		columns[i] = column
+	}
+	if !hasValue {
+		return nil, fmt.Errorf("%s: missing value column", filename)
+	}
+
+	var entries []ListEntry
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			entries = append(entries, ListEntry{Line: parseErr.Line, Err: parseErr.Err})
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error reading file %s: %w", filename, err)
+		}
+		line, _ := reader.FieldPos(0)
+		if len(record) > len(columns) {
+			entries = append(entries, ListEntry{
+				Line:  line,
+				Value: strings.Join(record, ","),
+				Err:   fmt.Errorf("%d fields, more than the %d columns", len(record), len(columns)),
+			})
+			continue
+		}
+
+		var fields entryFields
+		for i, value := range record {
+			value = strings.TrimSpace(value)
+			switch columns[i] {
+			case "value":
+				fields.Value = value
+			case "category":
+				fields.Category = value
+			case "source":
+				fields.Source = value
+			case "added":
+				fields.Added = value
+			case "expires":
+				fields.Expires = value
+			case "reason":
+				fields.Reason = value
+			}
+		}
+
+		entry, err := fields.entry(line)
+		if err != nil {
+			entry = ListEntry{Line: line, Value: strings.Join(record, ","), Err: err}
+		}
+		if entry.Value != "" || entry.Err != nil {
+			entries = append(entries, entry)
+		}
+	}
+
+	return entries, nil
+}
+
+// readJSONLEntries reads JSONL list entries, one object per line
+func readJSONLEntries(filename string, r io.Reader) ([]ListEntry, error) {
+	var entries []ListEntry
+	scanner := bufio.NewScanner(r)
+
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		var fields entryFields
+		decoder := json.NewDecoder(strings.NewReader(line))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&fields); err != nil {
+			entries = append(entries, ListEntry{Line: lineNum, Value: line, Err: err})
+			continue
+		}
+
+		entry, err := fields.entry(lineNum)
+		if err != nil {
+			entry = ListEntry{Line: lineNum, Value: line, Err: err}
+		}
+		if entry.Value != "" || entry.Err != nil {
+			entries = append(entries, entry)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading file %s: %w", filename, err)
+	}
+
+	return entries, nil
+}
+
+// entry validates the fields and converts them to the list entry on line
+func (f entryFields) entry(line int) (ListEntry, error) {
+	entry := ListEntry{
+		Line:     line,
+		Value:    strings.TrimSpace(f.Value),
+		Category: f.Category,
+		Source:   f.Source,
+		Reason:   f.Reason,
+	}
+
+	var err error
+	if entry.Added, err = parseEntryDate(f.Added); err != nil {
+		return ListEntry{}, fmt.Errorf("invalid added date: %w", err)
+	}
+	if entry.Expires, err = parseEntryDate(f.Expires); err != nil {
+		return ListEntry{}, fmt.Errorf("invalid expires date: %w", err)
+	}
+	return entry, nil
+}
+
+// parseEntryDate parses a YYYY-MM-DD or RFC 3339 date, "" gives the zero time
+func parseEntryDate(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.DateOnly, value); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%q is not a YYYY-MM-DD or RFC 3339 date", value)
+	}
+	return t, nil
+}
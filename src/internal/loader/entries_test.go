@@ -0,0 +1,227 @@
+package loader
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// writeEntries writes a list file named name holding content and returns
+// its path
+func writeEntries(t *testing.T, name, content string) string {
+	t.Helper()
+	filename := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(filename, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return filename
+}
+
+// normalizeLower lowercases values and rejects the ones with spaces
+func normalizeLower(value string) (string, error) {
+	if strings.ContainsAny(value, " \t") {
+		return "", errors.New("not a domain")
+	}
+	return strings.ToLower(value), nil
+}
+
+func TestLoadEntriesCSV(t *testing.T) {
+	filename := writeEntries(t, "list.csv", strings.Join([]string{
+		"# Comments before the header are skipped",
+		"Reason, VALUE ,category,expires",
+		`"Known burner, reported twice",burner.example,disposable,2030-01-02`,
+		`"multi-line`,
+		`reason",relay.example,relay`,
+		"no reason,Forward.example",
+		",,,",
+		"",
+	}, "\n"))
+
+	entries, err := LoadEntries(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []ListEntry{
+		{
+			Line:     3,
+			Value:    "burner.example",
+			Category: "disposable",
+			Reason:   "Known burner, reported twice",
+			Expires:  time.Date(2030, 1, 2, 0, 0, 0, 0, time.UTC),
+		},
+		{Line: 4, Value: "relay.example", Category: "relay", Reason: "multi-line\nreason"},
+		{Line: 6, Value: "Forward.example", Reason: "no reason"},
+	}
+	if len(entries) != len(want) {
+		t.Fatalf("entries = %+v, want %+v", entries, want)
+	}
+	for i := range want {
+		if entries[i] != want[i] {
+			t.Errorf("entry %d = %+v, want %+v", i, entries[i], want[i])
+		}
+	}
+}
+
+func TestLoadEntriesCSVHeader(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{"unknown column", "value,comment\na.example,x\n", `unknown column "comment"`},
+		{"missing value column", "category,reason\ndisposable,x\n", "missing value column"},
+		// A file without a header reads its first entry as one
+		{"no header", "a.example\nb.example\n", `unknown column "a.example"`},
+		{"empty", "", "failed to read header"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadEntries(writeEntries(t, "list.csv", tt.content))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("LoadEntries error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoadEntriesJSONL(t *testing.T) {
+	filename := writeEntries(t, "list.jsonl", strings.Join([]string{
+		`{"value": "burner.example", "category": "disposable", "added": "2024-05-01T10:00:00Z"}`,
+		"",
+		`{"value": " relay.example ", "source": "reports", "reason": "open relay"}`,
+		`{"value": ""}`,
+	}, "\n"))
+
+	entries, err := LoadEntries(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []ListEntry{
+		{
+			Line:     1,
+			Value:    "burner.example",
+			Category: "disposable",
+			Added:    time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC),
+		},
+		{Line: 3, Value: "relay.example", Source: "reports", Reason: "open relay"},
+	}
+	if len(entries) != len(want) {
+		t.Fatalf("entries = %+v, want %+v", entries, want)
+	}
+	for i := range want {
+		if entries[i] != want[i] {
+			t.Errorf("entry %d = %+v, want %+v", i, entries[i], want[i])
+		}
+	}
+}
+
+func TestLoadEntriesText(t *testing.T) {
+	filename := writeEntries(t, "list.txt", "# Header\na.example\n\n  b.example  # inline comment\n")
+
+	entries, err := LoadEntries(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []ListEntry{{Line: 2, Value: "a.example"}, {Line: 4, Value: "b.example"}}
+	if len(entries) != len(want) || entries[0] != want[0] || entries[1] != want[1] {
+		t.Errorf("entries = %+v, want %+v", entries, want)
+	}
+}
+
+func TestLoadEntriesBadLines(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		// Rejected lines and a fragment of each reason, in file order
+		lines   []int
+		reasons []string
+	}{
+		{
+			name: "csv",
+			file: "list.csv",
+			content: strings.Join([]string{
+				"value,category,added",
+				"good.example,disposable",
+				`bad "quote.example,disposable`,
+				"too.example,many,2024-01-01,fields",
+				"date.example,disposable,yesterday",
+				"has space.example",
+				"GOOD.example",
+			}, "\n"),
+			lines:   []int{3, 4, 5, 6},
+			reasons: []string{"bare", "more than the 3 columns", "invalid added date", "not a domain"},
+		},
+		{
+			name: "jsonl",
+			file: "list.jsonl",
+			content: strings.Join([]string{
+				`{"value": "good.example"}`,
+				`{"value": "broken.example"`,
+				`{"value": "extra.example", "comment": "x"}`,
+				`{"value": "date.example", "expires": "soon"}`,
+				`{"value": "has space.example"}`,
+				`{"value": "GOOD.example"}`,
+			}, "\n"),
+			lines:   []int{2, 3, 4, 5},
+			reasons: []string{"unexpected EOF", "unknown field", "invalid expires date", "not a domain"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filename := writeEntries(t, tt.file, tt.content)
+			entries, err := LoadEntries(filename)
+			if err != nil {
+				t.Fatalf("LoadEntries: %v", err)
+			}
+
+			// Unreadable rows are counted with the ones normalize rejects
+			kept, report := NormalizeEntries(filename, entries, normalizeLower)
+			if report.Entries != 6 || report.Loaded != 1 || report.Duplicates != 1 || report.Rejected != len(tt.lines) {
+				t.Errorf("report = %+v, want 6 entries, 1 loaded, 1 duplicate and %d rejected", report, len(tt.lines))
+			}
+			if len(kept) != 1 || kept[0].Value != "good.example" {
+				t.Errorf("kept = %+v, want good.example", kept)
+			}
+			if len(report.Errors) != len(tt.lines) {
+				t.Fatalf("errors = %+v, want %d", report.Errors, len(tt.lines))
+			}
+			for i, e := range report.Errors {
+				if e.Line != tt.lines[i] || !strings.Contains(e.Reason, tt.reasons[i]) {
+					t.Errorf("error %d = %+v, want line %d rejected for %q", i, e, tt.lines[i], tt.reasons[i])
+				}
+			}
+
+			err = report.CheckErrorRate(0.5)
+			if err == nil || !strings.Contains(err.Error(), "4 of 6 entries rejected") {
+				t.Errorf("CheckErrorRate error = %v, want the rejected entries reported", err)
+			}
+			if err := report.CheckErrorRate(0.7); err != nil {
+				t.Errorf("CheckErrorRate(0.7): %v", err)
+			}
+		})
+	}
+}
+
+func TestListEntryExpired(t *testing.T) {
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		expires time.Time
+		expired bool
+	}{
+		{time.Time{}, false},
+		{now.Add(time.Hour), false},
+		{now, true},
+		{now.Add(-time.Hour), true},
+	}
+	for _, tt := range tests {
+		if got := (ListEntry{Expires: tt.expires}).Expired(now); got != tt.expired {
+			t.Errorf("Expired with expiry %v = %v, want %v", tt.expires, got, tt.expired)
+		}
+	}
+}
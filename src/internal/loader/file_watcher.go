@@ -1,45 +1,44 @@
 package loader
 
 import (
+	"context"
 	"os"
+	"time"
 )
 
-// FileWatcher provides file watching capabilities (basic implementation)
+// FileWatcher provides file watching capabilities
 type FileWatcher struct {
-	filename     string
-	lastModified int64
-	loader       *FileLoader
+	filename string
+	last     os.FileInfo // nil while the file doesn't exist
+	loader   *FileLoader
 }
 
 // NewFileWatcher creates a new file watcher
 func NewFileWatcher(filename string) *FileWatcher {
 	info, _ := os.Stat(filename)
-	var lastMod int64
-	if info != nil {
-		lastMod = info.ModTime().Unix()
-	}
 
 	return &FileWatcher{
-		filename:     filename,
-		lastModified: lastMod,
-		loader:       NewFileLoader(),
+		filename: filename,
+		last:     info,
+		loader:   NewFileLoader(),
 	}
 }
 
-// HasChanged checks if the file has been modified
+// HasChanged checks if the file has been modified, replaced or created
+// since the last check
 func (fw *FileWatcher) HasChanged() bool {
 	info, err := os.Stat(fw.filename)
 	if err != nil {
 		return false
 	}
 
-	currentMod := info.ModTime().Unix()
-	if currentMod > fw.lastModified {
-		fw.lastModified = currentMod
-		return true
-	}
+	changed := fw.last == nil ||
+		!info.ModTime().Equal(fw.last.ModTime()) ||
+		info.Size() != fw.last.Size() ||
+		!os.SameFile(info, fw.last)
+	fw.last = info
 
-	return false
+	return changed
 }
 
 // LoadIfChanged loads the file only if it has been modified
@@ -55,3 +54,52 @@ func (fw *FileWatcher) LoadIfChanged() ([]string, bool, error) {
 
 	return items, true, nil
 }
+
+// Watch sends on the returned channel whenever the file changes, until ctx
+// is done. Changes are picked up with inotify where available, and by
+// checking the file every interval otherwise. Sends never block, so a slow
+// receiver sees a burst of changes as one.
+func (fw *FileWatcher) Watch(ctx context.Context, interval time.Duration) <-chan struct{} {
+	changes := make(chan struct{}, 1)
+
+	// Notifications are set up before returning so changes made once Watch
+	// returns aren't missed
+	events, err := notify(ctx, fw.filename)
+
+	go func() {
+		defer close(changes)
+
+		var poll <-chan time.Time
+		if err != nil {
+			ticker := time.NewTicker(interval)
+			defer ticker.Stop()
+			poll = ticker.C
+		}
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case _, ok := <-events:
+				if !ok {
+					// The notifier failed, fall back to polling
+					events = nil
+					ticker := time.NewTicker(interval)
+					defer ticker.Stop()
+					poll = ticker.C
+					continue
+				}
+			case <-poll:
+			}
+
+			if fw.HasChanged() {
+				select {
+				case changes <- struct{}{}:
+				default:
+				}
+			}
+		}
+	}()
+
+	return changes
+}
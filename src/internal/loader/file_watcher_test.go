@@ -0,0 +1,98 @@
+package loader
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+)
+
+// replaceFile writes content to a temporary file and renames it over
+// filename, the way list files are meant to be replaced
+func replaceFile(t *testing.T, filename, content string) {
+	t.Helper()
+	tmp := filename + ".tmp"
+	if err := os.WriteFile(tmp, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Rename(tmp, filename); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// waitChange fails the test unless changes sends within timeout
+func waitChange(t *testing.T, changes <-chan struct{}, timeout time.Duration) {
+	t.Helper()
+	select {
+	case <-changes:
+	case <-time.After(timeout):
+		t.Fatal("no change reported")
+	}
+}
+
+// noChange fails the test if changes sends within wait
+func noChange(t *testing.T, changes <-chan struct{}, wait time.Duration) {
+	t.Helper()
+	select {
+	case <-changes:
+		t.Fatal("change reported for an unchanged file")
+	case <-time.After(wait):
+	}
+}
+
+func TestFileWatcherNotify(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("inotify is only used on linux")
+	}
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "list.txt")
+	replaceFile(t, filename, "a.example\n")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	// Polling this rarely leaves notifications as the only way to notice
+	changes := NewFileWatcher(filename).Watch(ctx, time.Hour)
+
+	replaceFile(t, filename, "b.example\n")
+	waitChange(t, changes, 5*time.Second)
+
+	// Other files in the directory don't count as changes
+	replaceFile(t, filepath.Join(dir, "other.txt"), "c.example\n")
+	noChange(t, changes, 100*time.Millisecond)
+
+	if err := os.WriteFile(filename, []byte("b.example\nc.example\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	waitChange(t, changes, 5*time.Second)
+
+	cancel()
+	for range changes {
+	}
+}
+
+func TestFileWatcherPoll(t *testing.T) {
+	// A missing directory can't be watched with inotify, so it is polled
+	dir := filepath.Join(t.TempDir(), "lists")
+	filename := filepath.Join(dir, "list.txt")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	changes := NewFileWatcher(filename).Watch(ctx, 10*time.Millisecond)
+	noChange(t, changes, 50*time.Millisecond)
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	replaceFile(t, filename, "a.example\n")
+	waitChange(t, changes, 5*time.Second)
+	noChange(t, changes, 50*time.Millisecond)
+
+	replaceFile(t, filename, "a.example\nb.example\n")
+	waitChange(t, changes, 5*time.Second)
+
+	cancel()
+	for range changes {
+	}
+}
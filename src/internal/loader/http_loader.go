@@ -0,0 +1,258 @@
+package loader
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// DefaultMaxListSize is the largest list HTTPLoader downloads by default
+const DefaultMaxListSize = 64 << 20
+
+// HTTPLoader implements Loader for lists served over HTTP. Downloads are
+// kept in a cache directory, revalidated with ETag and If-Modified-Since,
+// and used as a fallback copy when the server can't be reached. A URL can
+// pin its content with a #sha256=<hex> fragment. Sources that are not
+// URLs are read as local files.
+type HTTPLoader struct {
+	client   *http.Client
+	cacheDir string
+	maxSize  int64
+	files    *FileLoader
+	mu       sync.Mutex // Serializes fetches so cache files aren't written concurrently
+}
+
+// cacheMeta holds the validators used to revalidate a cached copy
+type cacheMeta struct {
+	URL          string `json:"url"`
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"last_modified,omitempty"`
+}
+
+// NewHTTPLoader creates a new HTTP loader caching downloads in cacheDir.
+// A nil client uses http.DefaultClient and a maxSize of 0 uses
+// DefaultMaxListSize.
+func NewHTTPLoader(client *http.Client, cacheDir string, maxSize int64) *HTTPLoader {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	if maxSize <= 0 {
+		maxSize = DefaultMaxListSize
+	}
+	return &HTTPLoader{
+		client:   client,
+		cacheDir: cacheDir,
+		maxSize:  maxSize,
+		files:    NewFileLoader(),
+	}
+}
+
+// StaleError is returned by Fetch along with the path of the cached copy
+// of a list that couldn't be downloaded, when that copy is used instead
+type StaleError struct {
+	URL string // Redacted list URL
+	Err error  // Why the download failed
+}
+
+func (e *StaleError) Error() string {
+	return fmt.Sprintf("failed to fetch %s, using the cached copy: %v", e.URL, e.Err)
+}
+
+func (e *StaleError) Unwrap() error { return e.Err }
+
+// IsURL reports whether a list source is an HTTP or HTTPS URL
+func IsURL(source string) bool {
+	return strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://")
+}
+
+// Fetch returns a local path holding the list at source, downloading URLs
+// into the cache directory first. Local paths are returned unchanged. When
+// the download fails and the last good copy is used, its path is returned
+// with a *StaleError.
+func (hl *HTTPLoader) Fetch(ctx context.Context, source string) (string, error) {
+	if !IsURL(source) {
+		return source, nil
+	}
+
+	u, err := url.Parse(source)
+	if err != nil {
+		return "", fmt.Errorf("invalid list URL %s: %w", source, err)
+	}
+	var checksum string
+	if sum, ok := strings.CutPrefix(u.Fragment, "sha256="); ok {
+		checksum = strings.ToLower(sum)
+	}
+	u.Fragment = ""
+
+	hl.mu.Lock()
+	defer hl.mu.Unlock()
+
+	if err := os.MkdirAll(hl.cacheDir, 0o700); err != nil {
+		return "", fmt.Errorf("failed to create list cache directory: %w", err)
+	}
+	key := sha256.Sum256([]byte(u.String()))
+	// Keep the list format, which is chosen by extension
+	ext := ".txt"
+	if IsRichList(u.Path) {
+		ext = strings.ToLower(filepath.Ext(u.Path))
+	}
+	path := filepath.Join(hl.cacheDir, hex.EncodeToString(key[:8])+ext)
+
+	fetchErr := hl.download(ctx, u.String(), path, checksum)
+	if fetchErr == nil {
+		return path, nil
+	}
+
+	// Fall back to the last good copy, if it still matches the pinned checksum
+	if err := verifyChecksum(path, checksum); err != nil {
+		return "", fmt.Errorf("failed to fetch %s: %w", u.Redacted(), fetchErr)
+	}
+	return path, &StaleError{URL: u.Redacted(), Err: fetchErr}
+}
+
+// download refreshes the cached copy at path from rawURL, keeping the copy
+// when the server reports it unchanged
+func (hl *HTTPLoader) download(ctx context.Context, rawURL, path, checksum string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return err
+	}
+
+	// Only revalidate when the cached copy is still around
+	meta, _ := readCacheMeta(path + ".meta")
+	if _, err := os.Stat(path); err == nil && meta.URL == rawURL {
+		if meta.ETag != "" {
+			req.Header.Set("If-None-Match", meta.ETag)
+		}
+		if meta.LastModified != "" {
+			req.Header.Set("If-Modified-Since", meta.LastModified)
+		}
+	}
+
+	resp, err := hl.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotModified:
+		return verifyChecksum(path, checksum)
+	case resp.StatusCode != http.StatusOK:
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	case resp.ContentLength > hl.maxSize:
+		return fmt.Errorf("list is %d bytes, more than the %d byte limit", resp.ContentLength, hl.maxSize)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, hl.maxSize+1))
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+	if int64(len(body)) > hl.maxSize {
+		return fmt.Errorf("list is more than the %d byte limit", hl.maxSize)
+	}
+	sum := sha256.Sum256(body)
+	if checksum != "" && hex.EncodeToString(sum[:]) != checksum {
+		return fmt.Errorf("checksum mismatch, got sha256 %x", sum)
+	}
+
+	// Leave an unchanged copy alone, so watchers of it don't reload
+	if old, err := Checksum(path); err != nil || old != sum {
+		if err := writeFileAtomic(path, body); err != nil {
+			return err
+		}
+	}
+	meta = cacheMeta{
+		URL:          rawURL,
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+	}
+	data, err := json.Marshal(meta)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(path+".meta", data)
+}
+
+// readCacheMeta reads the revalidation metadata of a cached copy
+func readCacheMeta(filename string) (cacheMeta, error) {
+	var meta cacheMeta
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return meta, err
+	}
+	err = json.Unmarshal(data, &meta)
+	return meta, err
+}
+
+// verifyChecksum checks that a file exists and, when checksum is set,
+// that its SHA-256 matches
+func verifyChecksum(filename, checksum string) error {
+	sum, err := Checksum(filename)
+	if err != nil {
+		return err
+	}
+	if checksum != "" && hex.EncodeToString(sum[:]) != checksum {
+		return fmt.Errorf("checksum mismatch for %s, got sha256 %x", filename, sum)
+	}
+	return nil
+}
+
+// writeFileAtomic writes data to a temporary file and renames it over
+// filename so readers never see a partial file
+func writeFileAtomic(filename string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(filename), filepath.Base(filename)+".tmp*")
+	if err != nil {
+		return fmt.Errorf("failed to create cache file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write cache file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write cache file: %w", err)
+	}
+	return os.Rename(tmp.Name(), filename)
+}
+
+// LoadFromFile loads domains/emails from a URL or a local file
+func (hl *HTTPLoader) LoadFromFile(source string) ([]string, error) {
+	path, err := hl.Fetch(context.Background(), source)
+	var stale *StaleError
+	if err != nil && !errors.As(err, &stale) {
+		return nil, err
+	}
+	return hl.files.LoadFromFile(path)
+}
+
+// LoadFromFiles loads items from multiple URLs or local files
+func (hl *HTTPLoader) LoadFromFiles(sources []string) ([]string, error) {
+	var allItems []string
+
+	for _, source := range sources {
+		items, err := hl.LoadFromFile(source)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load from %s: %w", source, err)
+		}
+		allItems = append(allItems, items...)
+	}
+
+	return removeDuplicates(allItems), nil
+}
+
+// LoadFromString loads items from a string content
+func (hl *HTTPLoader) LoadFromString(content string) ([]string, error) {
+	return hl.files.LoadFromString(content)
+}
@@ -0,0 +1,237 @@
+package loader
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+// listServer serves body with an ETag, answering 304 to a matching
+// If-None-Match, and counts the full responses it sent
+type listServer struct {
+	body   atomic.Value // string
+	status atomic.Int32 // Forced status, 0 to serve the list
+	full   atomic.Int32 // 200 responses sent
+	cond   atomic.Int32 // 304 responses sent
+}
+
+func newListServer(t *testing.T, body string) (*listServer, *httptest.Server) {
+	t.Helper()
+	ls := &listServer{}
+	ls.body.Store(body)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if status := ls.status.Load(); status != 0 {
+			w.WriteHeader(int(status))
+			return
+		}
+		body := ls.body.Load().(string)
+		sum := sha256.Sum256([]byte(body))
+		etag := `"` + hex.EncodeToString(sum[:8]) + `"`
+		if r.Header.Get("If-None-Match") == etag {
+			ls.cond.Add(1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		ls.full.Add(1)
+		w.Header().Set("ETag", etag)
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(srv.Close)
+	return ls, srv
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestHTTPLoaderFetch(t *testing.T) {
+	ls, srv := newListServer(t, "a.com\nb.com\n")
+	hl := NewHTTPLoader(srv.Client(), t.TempDir(), 0)
+
+	path, err := hl.Fetch(context.Background(), srv.URL+"/list.txt")
+	if err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+	if got := readFile(t, path); got != "a.com\nb.com\n" {
+		t.Errorf("cached copy = %q", got)
+	}
+	if ls.full.Load() != 1 {
+		t.Errorf("full responses = %d, want 1", ls.full.Load())
+	}
+}
+
+func TestHTTPLoaderFetchNotModified(t *testing.T) {
+	ls, srv := newListServer(t, "a.com\n")
+	hl := NewHTTPLoader(srv.Client(), t.TempDir(), 0)
+
+	first, err := hl.Fetch(context.Background(), srv.URL+"/list.txt")
+	if err != nil {
+		t.Fatalf("first Fetch: %v", err)
+	}
+	before, err := os.Stat(first)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	second, err := hl.Fetch(context.Background(), srv.URL+"/list.txt")
+	if err != nil {
+		t.Fatalf("second Fetch: %v", err)
+	}
+	if second != first {
+		t.Errorf("path changed from %s to %s", first, second)
+	}
+	if ls.full.Load() != 1 || ls.cond.Load() != 1 {
+		t.Errorf("responses = %d full and %d not modified, want 1 and 1", ls.full.Load(), ls.cond.Load())
+	}
+	after, err := os.Stat(second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !os.SameFile(before, after) {
+		t.Error("cached copy was rewritten although unchanged")
+	}
+}
+
+func TestHTTPLoaderFetchUpdated(t *testing.T) {
+	ls, srv := newListServer(t, "a.com\n")
+	hl := NewHTTPLoader(srv.Client(), t.TempDir(), 0)
+
+	if _, err := hl.Fetch(context.Background(), srv.URL+"/list.txt"); err != nil {
+		t.Fatalf("first Fetch: %v", err)
+	}
+	ls.body.Store("a.com\nc.com\n")
+	path, err := hl.Fetch(context.Background(), srv.URL+"/list.txt")
+	if err != nil {
+		t.Fatalf("second Fetch: %v", err)
+	}
+	if got := readFile(t, path); got != "a.com\nc.com\n" {
+		t.Errorf("cached copy = %q, want the new list", got)
+	}
+}
+
+func TestHTTPLoaderFetchOversize(t *testing.T) {
+	_, srv := newListServer(t, strings.Repeat("a.com\n", 100))
+	hl := NewHTTPLoader(srv.Client(), t.TempDir(), 64)
+
+	_, err := hl.Fetch(context.Background(), srv.URL+"/list.txt")
+	if err == nil || !strings.Contains(err.Error(), "byte limit") {
+		t.Fatalf("Fetch error = %v, want a size limit error", err)
+	}
+}
+
+func TestHTTPLoaderFetchChecksum(t *testing.T) {
+	body := "a.com\n"
+	sum := sha256.Sum256([]byte(body))
+	_, srv := newListServer(t, body)
+
+	tests := []struct {
+		name     string
+		checksum string
+		wantErr  bool
+	}{
+		{"match", hex.EncodeToString(sum[:]), false},
+		{"match upper case", strings.ToUpper(hex.EncodeToString(sum[:])), false},
+		{"mismatch", strings.Repeat("0", 64), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hl := NewHTTPLoader(srv.Client(), t.TempDir(), 0)
+			_, err := hl.Fetch(context.Background(), srv.URL+"/list.txt#sha256="+tt.checksum)
+			if tt.wantErr {
+				if err == nil || !strings.Contains(err.Error(), "checksum mismatch") {
+					t.Errorf("Fetch error = %v, want a checksum mismatch", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("Fetch: %v", err)
+			}
+		})
+	}
+}
+
+func TestHTTPLoaderFetchFallback(t *testing.T) {
+	ls, srv := newListServer(t, "a.com\n")
+	hl := NewHTTPLoader(srv.Client(), t.TempDir(), 0)
+
+	first, err := hl.Fetch(context.Background(), srv.URL+"/list.txt")
+	if err != nil {
+		t.Fatalf("first Fetch: %v", err)
+	}
+
+	ls.status.Store(http.StatusInternalServerError)
+	path, err := hl.Fetch(context.Background(), srv.URL+"/list.txt")
+	var stale *StaleError
+	if !errors.As(err, &stale) {
+		t.Fatalf("Fetch error = %v, want a *StaleError", err)
+	}
+	if !strings.Contains(stale.Err.Error(), "500") {
+		t.Errorf("stale cause = %v, want the server error", stale.Err)
+	}
+	if path != first {
+		t.Errorf("path = %s, want the cached copy %s", path, first)
+	}
+	if got := readFile(t, path); got != "a.com\n" {
+		t.Errorf("cached copy = %q", got)
+	}
+
+	// LoadFromFile uses the cached copy without failing
+	items, err := hl.LoadFromFile(srv.URL + "/list.txt")
+	if err != nil {
+		t.Fatalf("LoadFromFile: %v", err)
+	}
+	if len(items) != 1 || items[0] != "a.com" {
+		t.Errorf("items = %v, want [a.com]", items)
+	}
+}
+
+func TestHTTPLoaderFetchNoFallback(t *testing.T) {
+	ls, srv := newListServer(t, "a.com\n")
+	ls.status.Store(http.StatusNotFound)
+	hl := NewHTTPLoader(srv.Client(), t.TempDir(), 0)
+
+	_, err := hl.Fetch(context.Background(), srv.URL+"/list.txt")
+	var stale *StaleError
+	if err == nil || errors.As(err, &stale) {
+		t.Fatalf("Fetch error = %v, want a failure without a cached copy", err)
+	}
+}
+
+func TestHTTPLoaderFetchFallbackChecksum(t *testing.T) {
+	ls, srv := newListServer(t, "a.com\n")
+	hl := NewHTTPLoader(srv.Client(), t.TempDir(), 0)
+
+	if _, err := hl.Fetch(context.Background(), srv.URL+"/list.txt"); err != nil {
+		t.Fatalf("first Fetch: %v", err)
+	}
+
+	// A cached copy that doesn't match the pinned checksum isn't used
+	ls.status.Store(http.StatusInternalServerError)
+	_, err := hl.Fetch(context.Background(), srv.URL+"/list.txt#sha256="+strings.Repeat("0", 64))
+	var stale *StaleError
+	if err == nil || errors.As(err, &stale) {
+		t.Fatalf("Fetch error = %v, want a failure", err)
+	}
+}
+
+func TestHTTPLoaderFetchLocal(t *testing.T) {
+	hl := NewHTTPLoader(nil, t.TempDir(), 0)
+	path, err := hl.Fetch(context.Background(), "data/free.txt")
+	if err != nil || path != "data/free.txt" {
+		t.Errorf("Fetch = %q, %v, want the local path unchanged", path, err)
+	}
+}
@@ -2,8 +2,11 @@
 //
 // The package is organized into the following files:
 //   - file_loader.go: Basic file loading implementation
+//   - entries.go: Plain, CSV and JSONL list files with per-entry metadata
+//   - report.go: Entry normalization and load reports with line-level diagnostics
 //   - batch_loader.go: Parallel loading capabilities
-//   - file_watcher.go: File change monitoring
+//   - file_watcher.go: File change monitoring, with inotify on Linux (notify_*.go) and polling elsewhere
+//   - http_loader.go: Lists fetched over HTTP with a local fallback copy
 //   - utils.go: Utility functions for file validation and statistics
 //
 // Usage:
@@ -18,6 +21,9 @@
 //	if watcher.HasChanged() {
 //	    items, changed, err := watcher.LoadIfChanged()
 //	}
+//
+//	httpLoader := NewHTTPLoader(nil, "/var/cache/lists", 0)
+//	items, err := httpLoader.LoadFromFile("https://example.com/disposable.txt")
 package loader
 
 // Loader interface for loading email lists
@@ -0,0 +1,55 @@
+//go:build linux
+
+package loader
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"golang.org/x/sys/unix"
+)
+
+// notify reports events in the directory holding filename using inotify.
+// Watching the directory rather than the file keeps working when the file
+// is replaced by a rename or a symlink swap, and callers stat the file to
+// tell whether it changed. The channel is closed when ctx is done or
+// reading events fails.
+func notify(ctx context.Context, filename string) (<-chan struct{}, error) {
+	fd, err := unix.InotifyInit1(unix.IN_CLOEXEC | unix.IN_NONBLOCK)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize inotify: %w", err)
+	}
+	mask := uint32(unix.IN_CLOSE_WRITE | unix.IN_MOVED_TO | unix.IN_ATTRIB)
+	if _, err := unix.InotifyAddWatch(fd, filepath.Dir(filename), mask); err != nil {
+		unix.Close(fd)
+		return nil, fmt.Errorf("failed to watch %s: %w", filepath.Dir(filename), err)
+	}
+
+	// A non-blocking descriptor goes through the runtime poller, so closing
+	// the file unblocks a pending read
+	file := os.NewFile(uintptr(fd), "inotify")
+	go func() {
+		<-ctx.Done()
+		file.Close()
+	}()
+
+	events := make(chan struct{}, 1)
+	go func() {
+		defer close(events)
+
+		buf := make([]byte, 64*unix.SizeofInotifyEvent)
+		for {
+			if _, err := file.Read(buf); err != nil {
+				return
+			}
+			select {
+			case events <- struct{}{}:
+			default:
+			}
+		}
+	}()
+
+	return events, nil
+}
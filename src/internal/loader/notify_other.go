@@ -0,0 +1,13 @@
+//go:build !linux
+
+package loader
+
+import (
+	"context"
+	"errors"
+)
+
+// notify is not supported on this platform, so watchers poll
+func notify(ctx context.Context, filename string) (<-chan struct{}, error) {
+	return nil, errors.New("file notifications are not supported on this platform")
+}
@@ -0,0 +1,86 @@
+package loader
+
+import (
+	"fmt"
+)
+
+// maxReportedErrors is the number of rejected entries a load report lists,
+// further rejections are only counted
+const maxReportedErrors = 100
+
+// LoadReport describes how the entries of a list file were loaded
+type LoadReport struct {
+	File       string       `json:"file"`
+	Entries    int          `json:"entries"` // Entries read from the file
+	Loaded     int          `json:"loaded"`  // Distinct entries kept
+	Duplicates int          `json:"duplicates"`
+	Rejected   int          `json:"rejected"`
+	Errors     []EntryError `json:"errors,omitempty"`   // The first rejected entries
+	Snapshot   bool         `json:"snapshot,omitempty"` // Restored from a snapshot without reading entries
+}
+
+// EntryError is an entry rejected while loading a list
+type EntryError struct {
+	Line   int    `json:"line"`
+	Value  string `json:"value"`
+	Reason string `json:"reason"`
+}
+
+// ErrorRate returns the share of entries read that were rejected
+func (r LoadReport) ErrorRate() float64 {
+	if r.Entries == 0 {
+		return 0
+	}
+	return float64(r.Rejected) / float64(r.Entries)
+}
+
+// CheckErrorRate fails when more than maxRate of the entries were rejected,
+// citing the first rejected entry
+func (r LoadReport) CheckErrorRate(maxRate float64) error {
+	if r.ErrorRate() <= maxRate {
+		return nil
+	}
+	first := r.Errors[0]
+	return fmt.Errorf("%s: %d of %d entries rejected (%.1f%%), more than the %.1f%% allowed, first at line %d: %q: %s",
+		r.File, r.Rejected, r.Entries, 100*r.ErrorRate(), 100*maxRate, first.Line, first.Value, first.Reason)
+}
+
+// NormalizeEntries maps the values of entries through normalize, rejecting
+// the entries that couldn't be read or it fails for, and dropping
+// duplicates of a normalized value.
+// It returns the entries kept, with their normalized values, and a report
+// of the load.
+func NormalizeEntries(filename string, entries []ListEntry, normalize func(string) (string, error)) ([]ListEntry, LoadReport) {
+	report := LoadReport{File: filename, Entries: len(entries)}
+	seen := make(map[string]bool, len(entries))
+
+	kept := entries[:0]
+	for _, entry := range entries {
+		value, err := entry.Value, entry.Err
+		if err == nil {
+			value, err = normalize(entry.Value)
+		}
+		if err != nil {
+			report.Rejected++
+			if len(report.Errors) < maxReportedErrors {
+				report.Errors = append(report.Errors, EntryError{
+					Line:   entry.Line,
+					Value:  entry.Value,
+					Reason: err.Error(),
+				})
+			}
+			continue
+		}
+		if seen[value] {
+			report.Duplicates++
+			continue
+		}
+		seen[value] = true
+
+		entry.Value = value
+		kept = append(kept, entry)
+	}
+
+	report.Loaded = len(kept)
+	return kept, report
+}
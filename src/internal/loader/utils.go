@@ -1,7 +1,9 @@
 package loader
 
 import (
+	"crypto/sha256"
 	"fmt"
+	"io"
 	"os"
 )
 
@@ -68,3 +70,21 @@ func GetFileStats(filename string) (map[string]interface{}, error) {
 
 	return stats, nil
 }
+
+// Checksum returns the SHA-256 of a file's contents
+func Checksum(filename string) ([32]byte, error) {
+	var sum [32]byte
+
+	file, err := os.Open(filename)
+	if err != nil {
+		return sum, fmt.Errorf("failed to open file %s: %w", filename, err)
+	}
+	defer file.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, file); err != nil {
+		return sum, fmt.Errorf("error reading file %s: %w", filename, err)
+	}
+	copy(sum[:], h.Sum(nil))
+	return sum, nil
+}
@@ -0,0 +1,111 @@
+// Package normalizer computes the canonical form of email addresses, so
+// addresses that reach the same mailbox compare equal.
+package normalizer
+
+import (
+	"strings"
+
+	"golang.org/x/net/idna"
+)
+
+// providerRule describes how a mail provider maps addresses to mailboxes
+type providerRule struct {
+	alias      string // Domain this one is an alias of
+	ignoreDots bool   // Dots in the local part are not significant
+	separators string // Characters starting a subaddress tag
+}
+
+// providers holds the rules of well-known mail providers
+var providers = map[string]providerRule{
+	"gmail.com":      {ignoreDots: true, separators: "+"},
+	"googlemail.com": {alias: "gmail.com", ignoreDots: true, separators: "+"},
+
+	"outlook.com": {separators: "+"},
+	"hotmail.com": {separators: "+"},
+	"live.com":    {separators: "+"},
+	"msn.com":     {separators: "+"},
+
+	"yahoo.com":      {separators: "-"},
+	"ymail.com":      {separators: "-"},
+	"rocketmail.com": {separators: "-"},
+
+	"icloud.com": {separators: "+"},
+	"me.com":     {separators: "+"},
+	"mac.com":    {separators: "+"},
+
+	"fastmail.com":   {separators: "+"},
+	"protonmail.com": {separators: "+"},
+	"proton.me":      {separators: "+"},
+	"pm.me":          {separators: "+"},
+}
+
+// Canonical returns the canonical form of an email address: lowercased,
+// with the domain in IDNA A-label form, provider aliases resolved, and dots
+// and subaddress tags removed where the provider ignores them. The local
+// part of addresses at unknown providers is only lowercased, as it may be
+// significant.
+func Canonical(email string) string {
+	email = strings.ToLower(strings.TrimSpace(email))
+
+	at := strings.LastIndex(email, "@")
+	if at <= 0 || at == len(email)-1 {
+		return email
+	}
+	local, domain := email[:at], email[at+1:]
+
+	if ascii, err := ToASCII(domain); err == nil {
+		domain = ascii
+	}
+
+	rule, ok := providers[domain]
+	if !ok {
+		return local + "@" + domain
+	}
+
+	if rule.alias != "" {
+		domain = rule.alias
+	}
+
+	if rule.separators != "" {
+		if i := strings.IndexAny(local, rule.separators); i > 0 {
+			local = local[:i]
+		}
+	}
+
+	if rule.ignoreDots {
+		local = strings.ReplaceAll(local, ".", "")
+	}
+
+	if local == "" {
+		return email[:at] + "@" + domain
+	}
+
+	return local + "@" + domain
+}
+
+// CanonicalDomain returns the domain a provider domain is an alias of, or
+// the domain itself
+func CanonicalDomain(domain string) string {
+	domain = strings.ToLower(strings.TrimSpace(domain))
+	if rule, ok := providers[domain]; ok && rule.alias != "" {
+		return rule.alias
+	}
+	return domain
+}
+
+// ToASCII converts a domain to its IDNA A-label form, validating it as a
+// host name on the way
+func ToASCII(domain string) (string, error) {
+	domain = strings.TrimSuffix(strings.TrimSpace(domain), ".")
+	return idna.Lookup.ToASCII(domain)
+}
+
+// ToUnicode converts a domain to its IDNA U-label form, returning the
+// domain unchanged if it can't be converted
+func ToUnicode(domain string) string {
+	unicode, err := idna.Lookup.ToUnicode(domain)
+	if err != nil {
+		return domain
+	}
+	return unicode
+}
@@ -0,0 +1,91 @@
+package normalizer
+
+import "testing"
+
+func TestCanonical(t *testing.T) {
+	tests := []struct {
+		name  string
+		email string
+		want  string
+	}{
+		// Provider rules
+		{"gmail dots", "j.o.h.n@gmail.com", "john@gmail.com"},
+		{"gmail tag", "john+news@gmail.com", "john@gmail.com"},
+		{"gmail dots and tag", "J.o.h.n+news+more@GMail.com", "john@gmail.com"},
+		{"googlemail alias", "j.ohn+x@googlemail.com", "john@gmail.com"},
+		{"outlook tag", "first.last+shop@outlook.com", "first.last@outlook.com"},
+		{"hotmail tag", "user+a@hotmail.com", "user@hotmail.com"},
+		{"live tag", "user+a@live.com", "user@live.com"},
+		{"msn tag", "user+a@msn.com", "user@msn.com"},
+		{"yahoo tag", "first.last-shop@yahoo.com", "first.last@yahoo.com"},
+		{"ymail tag", "user-a@ymail.com", "user@ymail.com"},
+		{"rocketmail tag", "user-a@rocketmail.com", "user@rocketmail.com"},
+		{"icloud tag", "user+a@icloud.com", "user@icloud.com"},
+		{"me tag", "user+a@me.com", "user@me.com"},
+		{"mac tag", "user+a@mac.com", "user@mac.com"},
+		{"fastmail tag", "user+a@fastmail.com", "user@fastmail.com"},
+		{"protonmail tag", "user+a@protonmail.com", "user@protonmail.com"},
+		{"proton tag", "user+a@proton.me", "user@proton.me"},
+		{"pm tag", "user+a@pm.me", "user@pm.me"},
+		{"case and space", "  John@Example.COM ", "john@example.com"},
+		{"idn domain", "user@Bücher.de", "user@xn--bcher-kva.de"},
+
+		// Addresses left alone
+		{"unknown provider dots and tag", "j.o.h.n+1@example.com", "j.o.h.n+1@example.com"},
+		{"outlook keeps dots", "first.last@outlook.com", "first.last@outlook.com"},
+		{"yahoo keeps plus", "user+a@yahoo.com", "user+a@yahoo.com"},
+		{"gmail keeps dash", "first-last@gmail.com", "first-last@gmail.com"},
+		{"leading separator", "+tag@gmail.com", "+tag@gmail.com"},
+		{"only dots", "...@gmail.com", "...@gmail.com"},
+		{"gmail subdomain", "j.ohn+x@mail.gmail.com", "j.ohn+x@mail.gmail.com"},
+		{"no at", "not-an-email", "not-an-email"},
+		{"no local part", "@gmail.com", "@gmail.com"},
+		{"no domain", "john@", "john@"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Canonical(tt.email); got != tt.want {
+				t.Errorf("Canonical(%q) = %q, want %q", tt.email, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCanonicalDomain(t *testing.T) {
+	tests := map[string]string{
+		"googlemail.com":  "gmail.com",
+		"GoogleMail.com ": "gmail.com",
+		"gmail.com":       "gmail.com",
+		"outlook.com":     "outlook.com",
+		"example.com":     "example.com",
+	}
+	for domain, want := range tests {
+		if got := CanonicalDomain(domain); got != want {
+			t.Errorf("CanonicalDomain(%q) = %q, want %q", domain, got, want)
+		}
+	}
+}
+
+func TestToASCII(t *testing.T) {
+	tests := []struct {
+		domain  string
+		want    string
+		wantErr bool
+	}{
+		{"bücher.de", "xn--bcher-kva.de", false},
+		{"xn--bcher-kva.de", "xn--bcher-kva.de", false},
+		{"example.com.", "example.com", false},
+		{"exa mple.com", "", true},
+	}
+	for _, tt := range tests {
+		got, err := ToASCII(tt.domain)
+		if (err != nil) != tt.wantErr || (!tt.wantErr && got != tt.want) {
+			t.Errorf("ToASCII(%q) = %q, %v, want %q", tt.domain, got, err, tt.want)
+		}
+	}
+
+	if got := ToUnicode("xn--bcher-kva.de"); got != "bücher.de" {
+		t.Errorf("ToUnicode = %q, want bücher.de", got)
+	}
+}
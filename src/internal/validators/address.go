@@ -0,0 +1,194 @@
+package validators
+
+import (
+	"fmt"
+	"net/netip"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/wizenheimer/bloombox/internal/normalizer"
+)
+
+const (
+	maxLocalPartLength = 64
+	maxDomainLength    = 253
+	maxAddressLength   = 254
+)
+
+// Address is a parsed email address
+type Address struct {
+	LocalPart   string // Local part as written, may contain UTF-8
+	Domain      string // Domain in U-label form
+	ASCIIDomain string // Domain in A-label form, used for lookups
+	IsEAI       bool   // Local part contains non-ASCII characters (RFC 6531)
+	IsIDN       bool   // Domain contains internationalized labels
+	IsIPLiteral bool   // Domain is an address literal such as [192.0.2.1]
+}
+
+// String returns the address with its domain in U-label form
+func (a *Address) String() string {
+	return a.LocalPart + "@" + a.Domain
+}
+
+// ASCII returns the address with its domain in A-label form
+func (a *Address) ASCII() string {
+	return a.LocalPart + "@" + a.ASCIIDomain
+}
+
+// AddressError describes why an address failed to parse
+type AddressError struct {
+	Part   string // "address", "local_part" or "domain"
+	Reason string
+}
+
+func (e *AddressError) Error() string {
+	return fmt.Sprintf("invalid %s: %s", strings.ReplaceAll(e.Part, "_", " "), e.Reason)
+}
+
+// ParseAddress parses a bare email address following RFC 5321 and its
+// internationalized extensions (RFC 6531/6532): the local part may hold
+// UTF-8 characters and the domain may be an IDN, which is validated and
+// converted to its A-label form.
+func ParseAddress(email string) (*Address, error) {
+	if !utf8.ValidString(email) {
+		return nil, &AddressError{Part: "address", Reason: "not valid UTF-8"}
+	}
+
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		return nil, &AddressError{Part: "address", Reason: "missing @"}
+	}
+	local, domain := email[:at], email[at+1:]
+
+	if err := validateLocalPart(local); err != nil {
+		return nil, err
+	}
+
+	addr := &Address{
+		LocalPart: local,
+		IsEAI:     !isASCII(local),
+	}
+
+	if err := parseDomain(addr, domain); err != nil {
+		return nil, err
+	}
+
+	if len(addr.LocalPart)+1+len(addr.ASCIIDomain) > maxAddressLength {
+		return nil, &AddressError{Part: "address", Reason: "too long"}
+	}
+
+	return addr, nil
+}
+
+// validateLocalPart checks a dot-atom or quoted-string local part
+func validateLocalPart(local string) error {
+	if local == "" {
+		return &AddressError{Part: "local_part", Reason: "empty"}
+	}
+	if len(local) > maxLocalPartLength {
+		return &AddressError{Part: "local_part", Reason: "longer than 64 octets"}
+	}
+
+	if strings.HasPrefix(local, `"`) {
+		return validateQuotedString(local)
+	}
+
+	for _, atom := range strings.Split(local, ".") {
+		if atom == "" {
+			return &AddressError{Part: "local_part", Reason: "empty dot-separated segment"}
+		}
+		for _, r := range atom {
+			if !isAtext(r) {
+				return &AddressError{Part: "local_part", Reason: fmt.Sprintf("invalid character %q", r)}
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateQuotedString checks a quoted-string local part
+func validateQuotedString(local string) error {
+	if len(local) < 2 || !strings.HasSuffix(local, `"`) {
+		return &AddressError{Part: "local_part", Reason: "unterminated quoted string"}
+	}
+
+	escaped := false
+	for _, r := range local[1 : len(local)-1] {
+		switch {
+		case escaped:
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == '"':
+			return &AddressError{Part: "local_part", Reason: "unescaped quote"}
+		case r < ' ' || r == 0x7f:
+			return &AddressError{Part: "local_part", Reason: "control character"}
+		}
+	}
+	if escaped {
+		return &AddressError{Part: "local_part", Reason: "unterminated escape"}
+	}
+
+	return nil
+}
+
+// parseDomain validates a domain or address literal and fills in its forms
+func parseDomain(addr *Address, domain string) error {
+	if domain == "" {
+		return &AddressError{Part: "domain", Reason: "empty"}
+	}
+
+	if strings.HasPrefix(domain, "[") && strings.HasSuffix(domain, "]") {
+		literal := domain[1 : len(domain)-1]
+		literal = strings.TrimPrefix(literal, "IPv6:")
+		if _, err := netip.ParseAddr(literal); err != nil {
+			return &AddressError{Part: "domain", Reason: "invalid address literal"}
+		}
+		addr.Domain, addr.ASCIIDomain, addr.IsIPLiteral = domain, domain, true
+		return nil
+	}
+
+	ascii, err := normalizer.ToASCII(domain)
+	if err != nil {
+		return &AddressError{Part: "domain", Reason: err.Error()}
+	}
+	if len(ascii) > maxDomainLength {
+		return &AddressError{Part: "domain", Reason: "longer than 253 octets"}
+	}
+	if !strings.Contains(ascii, ".") {
+		return &AddressError{Part: "domain", Reason: "not a fully qualified domain"}
+	}
+	for _, label := range strings.Split(ascii, ".") {
+		if label == "" || len(label) > 63 {
+			return &AddressError{Part: "domain", Reason: "invalid label length"}
+		}
+	}
+
+	addr.ASCIIDomain = ascii
+	addr.Domain = normalizer.ToUnicode(ascii)
+	addr.IsIDN = addr.Domain != ascii
+	return nil
+}
+
+// isAtext reports whether r may appear in a dot-atom, including the
+// UTF-8 characters allowed by RFC 6532
+func isAtext(r rune) bool {
+	switch {
+	case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		return true
+	case r >= utf8.RuneSelf:
+		return r != utf8.RuneError
+	}
+	return strings.ContainsRune("!#$%&'*+-/=?^_`{|}~", r)
+}
+
+// isASCII reports whether s only holds ASCII characters
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,93 @@
+package validators
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParseAddress(t *testing.T) {
+	tests := []struct {
+		email string
+		want  Address
+	}{
+		{"user@example.com", Address{LocalPart: "user", Domain: "example.com", ASCIIDomain: "example.com"}},
+		{"first.last+tag@sub.example.co.uk", Address{LocalPart: "first.last+tag", Domain: "sub.example.co.uk", ASCIIDomain: "sub.example.co.uk"}},
+		{`"john doe"@example.com`, Address{LocalPart: `"john doe"`, Domain: "example.com", ASCIIDomain: "example.com"}},
+
+		// U-labels are converted to A-labels and back
+		{"user@bücher.de", Address{LocalPart: "user", Domain: "bücher.de", ASCIIDomain: "xn--bcher-kva.de", IsIDN: true}},
+		{"user@xn--bcher-kva.de", Address{LocalPart: "user", Domain: "bücher.de", ASCIIDomain: "xn--bcher-kva.de", IsIDN: true}},
+		{"user@BÜCHER.de", Address{LocalPart: "user", Domain: "bücher.de", ASCIIDomain: "xn--bcher-kva.de", IsIDN: true}},
+		{"user@例え.jp", Address{LocalPart: "user", Domain: "例え.jp", ASCIIDomain: "xn--r8jz45g.jp", IsIDN: true}},
+
+		// Non-ASCII local parts (RFC 6531)
+		{"müller@example.com", Address{LocalPart: "müller", Domain: "example.com", ASCIIDomain: "example.com", IsEAI: true}},
+		{"用户@例え.jp", Address{LocalPart: "用户", Domain: "例え.jp", ASCIIDomain: "xn--r8jz45g.jp", IsEAI: true, IsIDN: true}},
+
+		// Address literals
+		{"user@[192.0.2.1]", Address{LocalPart: "user", Domain: "[192.0.2.1]", ASCIIDomain: "[192.0.2.1]", IsIPLiteral: true}},
+		{"user@[IPv6:2001:db8::1]", Address{LocalPart: "user", Domain: "[IPv6:2001:db8::1]", ASCIIDomain: "[IPv6:2001:db8::1]", IsIPLiteral: true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.email, func(t *testing.T) {
+			addr, err := ParseAddress(tt.email)
+			if err != nil {
+				t.Fatalf("ParseAddress: %v", err)
+			}
+			if *addr != tt.want {
+				t.Errorf("ParseAddress = %+v, want %+v", *addr, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseAddressInvalid(t *testing.T) {
+	tests := []struct {
+		email string
+		part  string
+	}{
+		{"user.example.com", "address"},
+		{"user@\xff.com", "address"},
+		{"@example.com", "local_part"},
+		{"us..er@example.com", "local_part"},
+		{".user@example.com", "local_part"},
+		{"us er@example.com", "local_part"},
+		{"user�@example.com", "local_part"},
+		{`"unterminated@example.com`, "local_part"},
+		{`"a"b"@example.com`, "local_part"},
+		{strings.Repeat("a", 65) + "@example.com", "local_part"},
+		{"user@", "domain"},
+		{"user@localhost", "domain"},
+		{"user@exa mple.com", "domain"},
+		{"user@example..com", "domain"},
+		{"user@xn--zz.com", "domain"},
+		{"user@[999.0.0.1]", "domain"},
+		{"user@" + strings.Repeat("a", 64) + ".com", "domain"},
+		{strings.Repeat("a", 64) + "@" + strings.Repeat(strings.Repeat("b", 60)+".", 4) + "com", "address"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.email, func(t *testing.T) {
+			_, err := ParseAddress(tt.email)
+			var addrErr *AddressError
+			if !errors.As(err, &addrErr) {
+				t.Fatalf("ParseAddress error = %v, want an AddressError", err)
+			}
+			if addrErr.Part != tt.part {
+				t.Errorf("error part = %s (%v), want %s", addrErr.Part, err, tt.part)
+			}
+		})
+	}
+}
+
+func TestAddressASCII(t *testing.T) {
+	addr, err := ParseAddress("müller@bücher.de")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := addr.ASCII(); got != "müller@xn--bcher-kva.de" {
+		t.Errorf("ASCII() = %q, want the local part as written with an A-label domain", got)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/wizenheimer/bloombox/internal/loader"
@@ -12,37 +13,46 @@ import (
 
 // BanWordsValidator checks for banned words in email username
 type BanWordsValidator struct {
-	banWords map[string]bool
-	enabled  bool
+	list     *listSource[map[string]bool]
+	disabled atomic.Bool
 }
 
 // NewBanWordsValidator creates a new ban words validator
 func NewBanWordsValidator(filename string) (Validator, error) {
-	l := loader.NewFileLoader()
-	words, err := l.LoadFromFile(filename)
-	if err != nil {
-		return nil, fmt.Errorf("failed to load ban words file: %w", err)
-	}
+	list, err := newListSource(filename, func() (map[string]bool, int, error) {
+		l := loader.NewFileLoader()
+		words, err := l.LoadFromFile(filename)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to load ban words file: %w", err)
+		}
 
-	banWordsMap := make(map[string]bool)
-	for _, word := range words {
-		word = strings.ToLower(strings.TrimSpace(word))
-		if word != "" {
-			banWordsMap[word] = true
+		banWordsMap := make(map[string]bool)
+		for _, word := range words {
+			word = strings.ToLower(strings.TrimSpace(word))
+			if word != "" {
+				banWordsMap[word] = true
+			}
 		}
+		return banWordsMap, len(banWordsMap), nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return &BanWordsValidator{
-		banWords: banWordsMap,
-		enabled:  true,
+		list: list,
 	}, nil
 }
 
 func (v *BanWordsValidator) Name() string { return "banwords" }
 
-func (v *BanWordsValidator) IsEnabled() bool { return v.enabled }
+func (v *BanWordsValidator) IsEnabled() bool { return !v.disabled.Load() }
+
+func (v *BanWordsValidator) SetEnabled(enabled bool) { v.disabled.Store(!enabled) }
 
-func (v *BanWordsValidator) SetEnabled(enabled bool) { v.enabled = enabled }
+func (v *BanWordsValidator) Source() string { return v.list.Source() }
+
+func (v *BanWordsValidator) Reload() (int, error) { return v.list.Reload() }
 
 func (v *BanWordsValidator) Validate(ctx context.Context, email string) *ValidationResult {
 	start := time.Now()
@@ -54,15 +64,16 @@ func (v *BanWordsValidator) Validate(ctx context.Context, email string) *Validat
 		Details: make(map[string]interface{}),
 	}
 
+	banWords := v.list.Load()
 	var foundBanWords []string
 
 	// Check for exact matches
-	if v.banWords[localPartLower] {
+	if banWords[localPartLower] {
 		foundBanWords = append(foundBanWords, localPartLower)
 	}
 
 	// Check for partial matches (ban words contained in username)
-	for banWord := range v.banWords {
+	for banWord := range banWords {
 		if len(banWord) >= 3 && strings.Contains(localPartLower, banWord) {
 			// Avoid duplicates
 			found := false
@@ -88,6 +99,10 @@ func (v *BanWordsValidator) Validate(ctx context.Context, email string) *Validat
 		return "No banned words found"
 	}()
 
+	result.Code = CodeListNotListed
+	if hasBanWords {
+		result.Code = CodeListBannedWord
+	}
 	result.Details["local_part"] = localPart
 	result.Details["has_ban_words"] = hasBanWords
 	result.Details["ban_words_found"] = foundBanWords
@@ -2,7 +2,9 @@ package validators
 
 import (
 	"context"
-	"strings"
+	"fmt"
+	"os"
+	"sync/atomic"
 	"time"
 
 	"github.com/wizenheimer/bloombox/internal/filter"
@@ -11,50 +13,55 @@ import (
 
 // BlackListDomainsValidator checks against blacklisted domains
 type BlackListDomainsValidator struct {
-	filter  filter.Filter
-	enabled bool
+	list     *listSource[*listData]
+	disabled atomic.Bool
 }
 
 // NewBlackListDomainsValidator creates a new blacklisted domains validator
-func NewBlackListDomainsValidator(filename string, falsePositiveRate float64) (Validator, error) {
-	l := loader.NewFileLoader()
-	domains, err := l.LoadFromFile(filename)
-	if err != nil {
-		// If file doesn't exist, start with empty list
-		domains = []string{}
-	}
-
-	var f filter.Filter
-	if falsePositiveRate == 0 || len(domains) < 1000 {
-		f = filter.NewMapFilter()
-	} else {
-		f = filter.NewCuckooFilter(len(domains), falsePositiveRate)
-	}
-
-	for _, domain := range domains {
-		domain = strings.ToLower(strings.TrimSpace(domain))
-		if domain != "" {
-			f.Add(domain)
+func NewBlackListDomainsValidator(filename string, falsePositiveRate float64, filterType filter.Type, snapshotFile string, maxErrorRate, reloadMaxErrorRate float64) (Validator, error) {
+	list, err := newListDataSource(filename, reloadMaxErrorRate, func() (*listData, int, error) {
+		data := &listData{report: loader.LoadReport{File: filename}}
+		var err error
+		if _, statErr := os.Stat(filename); statErr != nil {
+			// If file doesn't exist, start with empty list
+			data.filter, err = newListFilter(filterType, 0, falsePositiveRate, 1000)
+		} else {
+			data, err = loadList(filename, snapshotFile, filterType, falsePositiveRate, 1000, maxErrorRate, normalizeDomainEntry)
+		}
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to load blacklisted domains file: %w", err)
 		}
+		return data, data.filter.Size(), nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return &BlackListDomainsValidator{
-		filter:  f,
-		enabled: true,
+		list: list,
 	}, nil
 }
 
 func (v *BlackListDomainsValidator) Name() string { return "blacklist_domains" }
 
-func (v *BlackListDomainsValidator) IsEnabled() bool { return v.enabled }
+func (v *BlackListDomainsValidator) IsEnabled() bool { return !v.disabled.Load() }
+
+func (v *BlackListDomainsValidator) SetEnabled(enabled bool) { v.disabled.Store(!enabled) }
+
+func (v *BlackListDomainsValidator) FilterStats() filter.Stats { return v.list.Load().filter.Stats() }
 
-func (v *BlackListDomainsValidator) SetEnabled(enabled bool) { v.enabled = enabled }
+func (v *BlackListDomainsValidator) LoadReport() loader.LoadReport { return v.list.Load().report }
+
+func (v *BlackListDomainsValidator) Source() string { return v.list.Source() }
+
+func (v *BlackListDomainsValidator) Reload() (int, error) { return v.list.Reload() }
 
 func (v *BlackListDomainsValidator) Validate(ctx context.Context, email string) *ValidationResult {
 	start := time.Now()
 
 	domain := extractDomain(email)
-	isBlacklisted := v.filter.Contains(domain)
+	list := v.list.Load()
+	entry, isBlacklisted := matchDomain(list, domain)
 
 	result := &ValidationResult{
 		Valid: !isBlacklisted,
@@ -65,12 +72,16 @@ func (v *BlackListDomainsValidator) Validate(ctx context.Context, email string)
 				return "Domain not in blacklist"
 			}
 		}(),
+		Code: listCode(isBlacklisted, CodeListBlacklistedDomain),
 		Details: map[string]interface{}{
 			"domain":         domain,
 			"is_blacklisted": isBlacklisted,
 		},
 		Duration: time.Since(start),
 	}
+	if isBlacklisted {
+		result.Details["matched_entry"] = list.matchedEntry(entry)
+	}
 
 	return result
 }
@@ -4,57 +4,60 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/wizenheimer/bloombox/internal/filter"
 	"github.com/wizenheimer/bloombox/internal/loader"
+	"github.com/wizenheimer/bloombox/internal/normalizer"
 )
 
 // BlackListEmailsValidator checks against specific blacklisted email addresses
 type BlackListEmailsValidator struct {
-	filter  filter.Filter
-	enabled bool
+	list     *listSource[*listData]
+	disabled atomic.Bool
 }
 
 // NewBlackListEmailsValidator creates a new blacklisted emails validator
-func NewBlackListEmailsValidator(filename string, falsePositiveRate float64) (Validator, error) {
-	l := loader.NewFileLoader()
-	emails, err := l.LoadFromFile(filename)
-	if err != nil {
-		return nil, fmt.Errorf("failed to load blacklisted emails file: %w", err)
-	}
-
-	var f filter.Filter
-	if falsePositiveRate == 0 || len(emails) < 1000 {
-		f = filter.NewMapFilter()
-	} else {
-		f = filter.NewCuckooFilter(len(emails), falsePositiveRate)
-	}
-
-	for _, email := range emails {
-		email = strings.ToLower(strings.TrimSpace(email))
-		if email != "" {
-			f.Add(email)
+func NewBlackListEmailsValidator(filename string, falsePositiveRate float64, filterType filter.Type, snapshotFile string, maxErrorRate, reloadMaxErrorRate float64) (Validator, error) {
+	// Store canonical forms so subaddress tags and dots can't bypass the list
+	list, err := newListDataSource(filename, reloadMaxErrorRate, func() (*listData, int, error) {
+		data, err := loadList(filename, snapshotFile, filterType, falsePositiveRate, 1000, maxErrorRate, normalizeEmailEntry)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to load blacklisted emails file: %w", err)
 		}
+		return data, data.filter.Size(), nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return &BlackListEmailsValidator{
-		filter:  f,
-		enabled: true,
+		list: list,
 	}, nil
 }
 
 func (v *BlackListEmailsValidator) Name() string { return "blacklist_emails" }
 
-func (v *BlackListEmailsValidator) IsEnabled() bool { return v.enabled }
+func (v *BlackListEmailsValidator) IsEnabled() bool { return !v.disabled.Load() }
 
-func (v *BlackListEmailsValidator) SetEnabled(enabled bool) { v.enabled = enabled }
+func (v *BlackListEmailsValidator) SetEnabled(enabled bool) { v.disabled.Store(!enabled) }
+
+func (v *BlackListEmailsValidator) FilterStats() filter.Stats { return v.list.Load().filter.Stats() }
+
+func (v *BlackListEmailsValidator) LoadReport() loader.LoadReport { return v.list.Load().report }
+
+func (v *BlackListEmailsValidator) Source() string { return v.list.Source() }
+
+func (v *BlackListEmailsValidator) Reload() (int, error) { return v.list.Reload() }
 
 func (v *BlackListEmailsValidator) Validate(ctx context.Context, email string) *ValidationResult {
 	start := time.Now()
 
 	emailLower := strings.ToLower(strings.TrimSpace(email))
-	isBlacklisted := v.filter.Contains(emailLower)
+	canonical := normalizer.Canonical(emailLower)
+	list := v.list.Load()
+	isBlacklisted := list.Contains(canonical)
 
 	result := &ValidationResult{
 		Valid: !isBlacklisted,
@@ -65,12 +68,17 @@ func (v *BlackListEmailsValidator) Validate(ctx context.Context, email string) *
 				return "Email not in blacklist"
 			}
 		}(),
+		Code: listCode(isBlacklisted, CodeListBlacklistedEmail),
 		Details: map[string]interface{}{
-			"email":          emailLower,
-			"is_blacklisted": isBlacklisted,
+			"email":           emailLower,
+			"canonical_email": canonical,
+			"is_blacklisted":  isBlacklisted,
 		},
 		Duration: time.Since(start),
 	}
+	if isBlacklisted {
+		result.Details["matched_entry"] = list.matchedEntry(canonical)
+	}
 
 	return result
 }
@@ -0,0 +1,277 @@
+package validators
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"sync/atomic"
+	"time"
+	"unicode"
+
+	"github.com/wizenheimer/bloombox/internal/normalizer"
+)
+
+// BlackListPatternsValidator checks addresses against glob and regex rules.
+//
+// Each line of a patterns file holds a rule ID followed by a pattern, and
+// rules whose ID starts with ! are exceptions that allow an address even
+// when another rule matches it:
+//
+//	test-accounts   test*@*
+//	long-random     re:^[a-z]{20,}\d{4}@
+//	example-all     *@example.com
+//	!example-ceo    ceo@example.com
+//
+// Globs match the whole address with * for any run of characters and ? for
+// a single one. Regexes start with re: and must be anchored with ^. Both
+// are matched against the lowercased address and its canonical form.
+type BlackListPatternsValidator struct {
+	list     *listSource[patternRules]
+	disabled atomic.Bool
+}
+
+// patternRules holds the blocking and exception rules of a patterns file
+type patternRules struct {
+	rules      *patternSet
+	exceptions *patternSet
+}
+
+// patternSet combines rules into one regexp, with a group per rule so a
+// single match reports which rule fired
+type patternSet struct {
+	re  *regexp.Regexp
+	ids []string // Rule ID of each group, by group index
+}
+
+// NewBlackListPatternsValidator creates a new blacklisted patterns validator
+func NewBlackListPatternsValidator(filename string) (Validator, error) {
+	list, err := newListSource(filename, func() (patternRules, int, error) {
+		return loadPatternRules(filename)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &BlackListPatternsValidator{
+		list: list,
+	}, nil
+}
+
+// loadPatternRules parses a patterns file and returns its rules with the
+// number of rules read
+func loadPatternRules(filename string) (patternRules, int, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return patternRules{}, 0, fmt.Errorf("failed to load blacklist patterns file: %w", err)
+	}
+	defer file.Close()
+
+	var rules, exceptions []patternRule
+	seen := make(map[string]bool)
+
+	scanner := bufio.NewScanner(file)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		rule, err := parsePatternRule(line)
+		if err != nil {
+			return patternRules{}, 0, fmt.Errorf("%s:%d: %w", filename, lineNum, err)
+		}
+		if seen[rule.id] {
+			return patternRules{}, 0, fmt.Errorf("%s:%d: duplicate rule ID %s", filename, lineNum, rule.id)
+		}
+		seen[rule.id] = true
+
+		if rule.exception {
+			exceptions = append(exceptions, rule)
+		} else {
+			rules = append(rules, rule)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return patternRules{}, 0, fmt.Errorf("error reading file %s: %w", filename, err)
+	}
+
+	return patternRules{
+		rules:      newPatternSet(rules),
+		exceptions: newPatternSet(exceptions),
+	}, len(seen), nil
+}
+
+// patternRule is a validated rule and the regexp source it compiles to
+type patternRule struct {
+	id        string
+	exception bool
+	expr      string
+	groups    int // Capturing groups within expr
+}
+
+// parsePatternRule parses and validates one line of a patterns file
+func parsePatternRule(line string) (patternRule, error) {
+	// The ID ends at the first space or tab, the pattern may hold either
+	sep := strings.IndexFunc(line, unicode.IsSpace)
+	if sep < 0 {
+		sep = len(line)
+	}
+	id, pattern := line[:sep], strings.TrimSpace(line[sep:])
+	if pattern == "" {
+		return patternRule{}, fmt.Errorf("expected a rule ID and a pattern, got %q", line)
+	}
+
+	rule := patternRule{id: id}
+	if strings.HasPrefix(id, "!") {
+		rule.id = id[1:]
+		rule.exception = true
+	}
+	if rule.id == "" {
+		return patternRule{}, fmt.Errorf("rule ID cannot be empty")
+	}
+
+	if expr, ok := strings.CutPrefix(pattern, "re:"); ok {
+		if !strings.HasPrefix(expr, "^") {
+			return patternRule{}, fmt.Errorf("rule %s: regex must be anchored with ^", rule.id)
+		}
+		// Wrap the regex so alternations inside it stay anchored
+		rule.expr = "^(?:" + expr + ")"
+	} else {
+		rule.expr = globToRegexp(strings.ToLower(pattern))
+	}
+
+	re, err := regexp.Compile(rule.expr)
+	if err != nil {
+		return patternRule{}, fmt.Errorf("rule %s: %w", rule.id, err)
+	}
+	rule.groups = re.NumSubexp()
+	return rule, nil
+}
+
+// globToRegexp converts a glob to an anchored regexp, * matches any run of
+// characters and ? matches a single character
+func globToRegexp(glob string) string {
+	var b strings.Builder
+	b.WriteString("^")
+	for _, r := range glob {
+		switch r {
+		case '*':
+			b.WriteString(".*")
+		case '?':
+			b.WriteString(".")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	b.WriteString("$")
+	return b.String()
+}
+
+// newPatternSet combines rules into a single alternation. Every rule is
+// anchored at the start, so the first matching rule in file order wins.
+//
+// Rule groups are found by index rather than by name, so groups a regex
+// rule declares itself can't be mistaken for another rule.
+func newPatternSet(rules []patternRule) *patternSet {
+	if len(rules) == 0 {
+		return nil
+	}
+
+	alternatives := make([]string, len(rules))
+	ids := []string{""} // Group 0 is the whole match
+	for i, rule := range rules {
+		alternatives[i] = "(" + rule.expr + ")"
+		ids = append(ids, rule.id)
+		ids = append(ids, make([]string, rule.groups)...)
+	}
+	re := regexp.MustCompile(strings.Join(alternatives, "|"))
+
+	return &patternSet{re: re, ids: ids}
+}
+
+// match returns the ID of the first rule matching s
+func (ps *patternSet) match(s string) (string, bool) {
+	if ps == nil {
+		return "", false
+	}
+	loc := ps.re.FindStringSubmatchIndex(s)
+	if loc == nil {
+		return "", false
+	}
+	for group := 1; group < len(ps.ids); group++ {
+		if ps.ids[group] != "" && loc[2*group] >= 0 {
+			return ps.ids[group], true
+		}
+	}
+	return "", false
+}
+
+func (v *BlackListPatternsValidator) Name() string { return "blacklist_patterns" }
+
+func (v *BlackListPatternsValidator) IsEnabled() bool { return !v.disabled.Load() }
+
+func (v *BlackListPatternsValidator) SetEnabled(enabled bool) { v.disabled.Store(!enabled) }
+
+func (v *BlackListPatternsValidator) Source() string { return v.list.Source() }
+
+func (v *BlackListPatternsValidator) Reload() (int, error) { return v.list.Reload() }
+
+func (v *BlackListPatternsValidator) Validate(ctx context.Context, email string) *ValidationResult {
+	start := time.Now()
+
+	emailLower := strings.ToLower(strings.TrimSpace(email))
+	canonical := normalizer.Canonical(emailLower)
+
+	// Check the canonical form too so dots and subaddress tags can't dodge
+	// a rule. An exception matching either form allows the address, so an
+	// exception for j.doe@gmail.com holds though the canonical form is
+	// jdoe@gmail.com.
+	rules := v.list.Load()
+	forms := []string{emailLower, canonical}
+	var ruleID, exceptionID string
+	for _, address := range forms {
+		if id, ok := rules.rules.match(address); ok {
+			ruleID = id
+			break
+		}
+	}
+	if ruleID != "" {
+		for _, address := range forms {
+			if id, ok := rules.exceptions.match(address); ok {
+				ruleID, exceptionID = "", id
+				break
+			}
+		}
+	}
+	isBlacklisted := ruleID != ""
+
+	result := &ValidationResult{
+		Valid: !isBlacklisted,
+		Message: func() string {
+			if isBlacklisted {
+				return fmt.Sprintf("Email matches blacklist rule %s", ruleID)
+			} else {
+				return "Email matches no blacklist rule"
+			}
+		}(),
+		Code: listCode(isBlacklisted, CodeListBlacklistedPattern),
+		Details: map[string]interface{}{
+			"email":           emailLower,
+			"canonical_email": canonical,
+			"is_blacklisted":  isBlacklisted,
+		},
+		Duration: time.Since(start),
+	}
+	if isBlacklisted {
+		result.Details["rule_id"] = ruleID
+	} else if exceptionID != "" {
+		result.Details["exception_rule_id"] = exceptionID
+	}
+
+	return result
+}
@@ -0,0 +1,171 @@
+package validators
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestGlobToRegexp(t *testing.T) {
+	tests := []struct {
+		glob    string
+		match   []string
+		noMatch []string
+	}{
+		{"test*@*", []string{"test@example.com", "tester1@mail.example.org"}, []string{"atest@example.com"}},
+		{"*@example.com", []string{"a@example.com", "@example.com"}, []string{"a@example.com.evil", "a@exampleXcom"}},
+		{"user?@example.com", []string{"user1@example.com"}, []string{"user@example.com", "user12@example.com"}},
+		{"a+b@(x).com", []string{"a+b@(x).com"}, []string{"aab@(x).com", "a+b@x.com"}},
+	}
+
+	for _, tt := range tests {
+		re := regexp.MustCompile(globToRegexp(tt.glob))
+		for _, s := range tt.match {
+			if !re.MatchString(s) {
+				t.Errorf("glob %q doesn't match %q", tt.glob, s)
+			}
+		}
+		for _, s := range tt.noMatch {
+			if re.MatchString(s) {
+				t.Errorf("glob %q matches %q", tt.glob, s)
+			}
+		}
+	}
+}
+
+func TestBlackListPatterns(t *testing.T) {
+	filename := writeList(t, t.TempDir(), "patterns.txt",
+		"# Comments and blank lines are skipped",
+		"",
+		"test-accounts   test*@*",
+		"long-random\tre:^[a-z]{20,}\\d{4}@",
+		"example-all     *@example.com",
+		"!example-ceo    ceo@example.com",
+		"!jdoe-dotted    j.doe@gmail.com",
+		"gmail-jdoe      jdoe@gmail.com",
+	)
+	v, err := NewBlackListPatternsValidator(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		email     string
+		rule      string
+		exception string
+	}{
+		{"test1@example.org", "test-accounts", ""},
+		{"TEST@Example.org", "test-accounts", ""},
+		{"abcdefghijklmnopqrstu1234@example.org", "long-random", ""},
+		{"shortname1234@example.org", "", ""},
+		{"someone@example.com", "example-all", ""},
+		// The first matching rule in file order is reported
+		{"tester@example.com", "test-accounts", ""},
+		// Exceptions allow an address another rule matches
+		{"ceo@example.com", "", "example-ceo"},
+		// Rules match the canonical form, exceptions either form
+		{"jdoe+news@gmail.com", "gmail-jdoe", ""},
+		{"j.doe@gmail.com", "", "jdoe-dotted"},
+		{"someone@example.org", "", ""},
+	}
+
+	for _, tt := range tests {
+		result := v.Validate(context.Background(), tt.email)
+		if result.Valid != (tt.rule == "") {
+			t.Errorf("%s valid = %v, want blocked by %q", tt.email, result.Valid, tt.rule)
+			continue
+		}
+		if tt.rule != "" {
+			if got := result.Details["rule_id"]; got != tt.rule {
+				t.Errorf("%s rule_id = %v, want %s", tt.email, got, tt.rule)
+			}
+			if result.Code != CodeListBlacklistedPattern {
+				t.Errorf("%s code = %s, want %s", tt.email, result.Code, CodeListBlacklistedPattern)
+			}
+		}
+		if got, _ := result.Details["exception_rule_id"].(string); got != tt.exception {
+			t.Errorf("%s exception_rule_id = %q, want %q", tt.email, got, tt.exception)
+		}
+	}
+}
+
+func TestParsePatternRule(t *testing.T) {
+	tests := []struct {
+		line      string
+		id        string
+		exception bool
+		expr      string
+	}{
+		{"test-accounts test*@*", "test-accounts", false, "^test.*@.*$"},
+		{"!example-ceo    ceo@example.com", "example-ceo", true, `^ceo@example\.com$`},
+		// The ID ends at the first tab, spaces in the pattern are kept
+		{"spaced\tre:^first last@", "spaced", false, "^(?:^first last@)"},
+		{"mixed \t re:^a\tb@", "mixed", false, "^(?:^a\tb@)"},
+	}
+
+	for _, tt := range tests {
+		rule, err := parsePatternRule(tt.line)
+		if err != nil {
+			t.Errorf("parsePatternRule(%q): %v", tt.line, err)
+			continue
+		}
+		if rule.id != tt.id || rule.exception != tt.exception || rule.expr != tt.expr {
+			t.Errorf("parsePatternRule(%q) = %+v, want ID %q, exception %v and expr %q", tt.line, rule, tt.id, tt.exception, tt.expr)
+		}
+	}
+}
+
+func TestBlackListPatternsGroups(t *testing.T) {
+	// Groups inside regex rules, named like the set's own or not, don't
+	// change which rule is reported
+	filename := writeList(t, t.TempDir(), "patterns.txt",
+		"first   re:^(?P<rule1>x)(y)?@",
+		"second  re:^(?P<rule0>a|b)(c)@",
+		"third   *@example.com",
+	)
+	v, err := NewBlackListPatternsValidator(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]string{
+		"xy@example.org":  "first",
+		"x@example.org":   "first",
+		"ac@example.org":  "second",
+		"bc@example.org":  "second",
+		"zzz@example.com": "third",
+	}
+	for email, rule := range tests {
+		result := v.Validate(context.Background(), email)
+		if got := result.Details["rule_id"]; got != rule {
+			t.Errorf("%s rule_id = %v, want %s", email, got, rule)
+		}
+	}
+}
+
+func TestBlackListPatternsInvalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		lines   []string
+		wantErr string
+	}{
+		{"missing pattern", []string{"lonely"}, "expected a rule ID and a pattern"},
+		{"blank pattern", []string{"lonely\t "}, "expected a rule ID and a pattern"},
+		{"empty exception ID", []string{"! ceo@example.com"}, "rule ID cannot be empty"},
+		{"unanchored regex", []string{"bad re:[a-z]+@"}, "must be anchored"},
+		{"invalid regex", []string{"bad re:^(@"}, "rule bad"},
+		{"duplicate ID", []string{"dup a@*", "dup b@*"}, "duplicate rule ID dup"},
+		{"duplicate exception ID", []string{"dup a@*", "!dup b@*"}, "duplicate rule ID dup"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filename := writeList(t, t.TempDir(), "patterns.txt", tt.lines...)
+			_, err := NewBlackListPatternsValidator(filename)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package validators
+
+import "errors"
+
+// Reason codes identify the outcome of a validator independently of its
+// message wording. They are part of the public API, see emailchecker.Code.
+const (
+	CodeSyntaxValid          = "syntax.valid"
+	CodeSyntaxInvalidAddress = "syntax.invalid_address"
+	CodeSyntaxInvalidLocal   = "syntax.invalid_local_part"
+	CodeSyntaxInvalidDomain  = "syntax.invalid_domain"
+
+	CodeMXFound     = "mx.found"
+	CodeMXImplicit  = "mx.implicit"
+	CodeMXNull      = "mx.null"
+	CodeMXNoRecords = "mx.no_records"
+
+	CodeSMTPMailboxExists       = "smtp.mailbox_exists"
+	CodeSMTPCatchAll            = "smtp.catch_all"
+	CodeSMTPConnected           = "smtp.connected"
+	CodeSMTPMailboxNotFound     = "smtp.mailbox_not_found"
+	CodeSMTPUserNotLocal        = "smtp.user_not_local"
+	CodeSMTPGreylisted          = "smtp.greylisted"
+	CodeSMTPRejected            = "smtp.rejected"
+	CodeSMTPConnectionFailed    = "smtp.connection_failed"
+	CodeSMTPProtocolError       = "smtp.protocol_error"
+	CodeSMTPSMTPUTF8Unsupported = "smtp.smtputf8_unsupported"
+	CodeSMTPMXLookupFailed      = "smtp.mx_lookup_failed"
+	CodeSMTPNullMX              = "smtp.null_mx"
+	CodeSMTPNoMX                = "smtp.no_mx"
+
+	CodeListNotListed          = "list.not_listed"
+	CodeListDisposable         = "list.disposable"
+	CodeListFree               = "list.free"
+	CodeListRole               = "list.role"
+	CodeListBannedWord         = "list.banned_word"
+	CodeListBlacklistedEmail   = "list.blacklisted_email"
+	CodeListBlacklistedDomain  = "list.blacklisted_domain"
+	CodeListBlacklistedPattern = "list.blacklisted_pattern"
+
+	CodeGravatarFound         = "gravatar.found"
+	CodeGravatarNotFound      = "gravatar.not_found"
+	CodeGravatarRequestFailed = "gravatar.request_failed"
+
+	CodeSuggestNone = "suggest.none"
+	CodeSuggestTypo = "suggest.typo"
+)
+
+// SyntaxCode returns the reason code for an address parsing error
+func SyntaxCode(err error) string {
+	var addrErr *AddressError
+	if errors.As(err, &addrErr) {
+		switch addrErr.Part {
+		case "local_part":
+			return CodeSyntaxInvalidLocal
+		case "domain":
+			return CodeSyntaxInvalidDomain
+		}
+	}
+	return CodeSyntaxInvalidAddress
+}
+
+// listCode returns code when an entry was found in a list
+func listCode(listed bool, code string) string {
+	if listed {
+		return code
+	}
+	return CodeListNotListed
+}
@@ -3,7 +3,7 @@ package validators
 import (
 	"context"
 	"fmt"
-	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/wizenheimer/bloombox/internal/filter"
@@ -12,49 +12,48 @@ import (
 
 // DisposableValidator checks against disposable email providers
 type DisposableValidator struct {
-	filter  filter.Filter
-	enabled bool
+	list     *listSource[*listData]
+	disabled atomic.Bool
 }
 
 // NewDisposableValidator creates a new disposable email validator
-func NewDisposableValidator(filename string, falsePositiveRate float64) (Validator, error) {
-	l := loader.NewFileLoader()
-	domains, err := l.LoadFromFile(filename)
-	if err != nil {
-		return nil, fmt.Errorf("failed to load disposable emails file: %w", err)
-	}
-
-	var f filter.Filter
-	if falsePositiveRate == 0 {
-		f = filter.NewMapFilter()
-	} else {
-		f = filter.NewCuckooFilter(len(domains), falsePositiveRate)
-	}
-
-	for _, domain := range domains {
-		domain = strings.ToLower(strings.TrimSpace(domain))
-		if domain != "" {
-			f.Add(domain)
+func NewDisposableValidator(filename string, falsePositiveRate float64, filterType filter.Type, snapshotFile string, maxErrorRate, reloadMaxErrorRate float64) (Validator, error) {
+	list, err := newListDataSource(filename, reloadMaxErrorRate, func() (*listData, int, error) {
+		data, err := loadList(filename, snapshotFile, filterType, falsePositiveRate, 0, maxErrorRate, normalizeDomainEntry)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to load disposable emails file: %w", err)
 		}
+		return data, data.filter.Size(), nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return &DisposableValidator{
-		filter:  f,
-		enabled: true,
+		list: list,
 	}, nil
 }
 
 func (v *DisposableValidator) Name() string { return "disposable" }
 
-func (v *DisposableValidator) IsEnabled() bool { return v.enabled }
+func (v *DisposableValidator) IsEnabled() bool { return !v.disabled.Load() }
 
-func (v *DisposableValidator) SetEnabled(enabled bool) { v.enabled = enabled }
+func (v *DisposableValidator) SetEnabled(enabled bool) { v.disabled.Store(!enabled) }
+
+func (v *DisposableValidator) FilterStats() filter.Stats { return v.list.Load().filter.Stats() }
+
+func (v *DisposableValidator) LoadReport() loader.LoadReport { return v.list.Load().report }
+
+func (v *DisposableValidator) Source() string { return v.list.Source() }
+
+func (v *DisposableValidator) Reload() (int, error) { return v.list.Reload() }
 
 func (v *DisposableValidator) Validate(ctx context.Context, email string) *ValidationResult {
 	start := time.Now()
 
 	domain := extractDomain(email)
-	isDisposable := v.filter.Contains(domain)
+	list := v.list.Load()
+	entry, isDisposable := matchDomain(list, domain)
 
 	result := &ValidationResult{
 		Valid: !isDisposable,
@@ -65,12 +64,16 @@ func (v *DisposableValidator) Validate(ctx context.Context, email string) *Valid
 				return "Not a disposable email"
 			}
 		}(),
+		Code: listCode(isDisposable, CodeListDisposable),
 		Details: map[string]interface{}{
 			"domain":        domain,
 			"is_disposable": isDisposable,
 		},
 		Duration: time.Since(start),
 	}
+	if isDisposable {
+		result.Details["matched_entry"] = list.matchedEntry(entry)
+	}
 
 	return result
 }
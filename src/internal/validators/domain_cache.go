@@ -0,0 +1,235 @@
+package validators
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sort"
+	"sync/atomic"
+	"time"
+
+	lru "github.com/hashicorp/golang-lru/v2"
+	"golang.org/x/sync/singleflight"
+)
+
+// MXLookup holds the outcome of an MX lookup for a domain
+type MXLookup struct {
+	Records []*net.MX // Sorted by preference
+	NullMX  bool      // Domain publishes a null MX (RFC 7505) and accepts no mail
+	Err     error
+}
+
+// HostLookup holds the outcome of an address lookup for a host, used as
+// the implicit MX fallback and to resolve MX hosts
+type HostLookup struct {
+	IPs []net.IPAddr
+	Err error
+}
+
+// domainEntry is a cached value with its expiry time
+type domainEntry[T any] struct {
+	value   T
+	expires time.Time
+}
+
+// DomainCache caches DNS-derived facts per domain: MX records, address
+// records, null MX and SMTP catch-all status. It is shared by all checks
+// of a checker, so checking many addresses at the same domain resolves
+// its records once per TTL.
+type DomainCache struct {
+	mx       *lru.Cache[string, domainEntry[*MXLookup]]
+	hosts    *lru.Cache[string, domainEntry[*HostLookup]]
+	catchAll *lru.Cache[string, domainEntry[bool]]
+	ttl      time.Duration
+	flight   singleflight.Group // Coalesces concurrent lookups of the same name
+
+	hits   atomic.Uint64
+	misses atomic.Uint64
+}
+
+// DomainCacheStats reports the state of a domain cache
+type DomainCacheStats struct {
+	MXEntries       int    `json:"mx_entries"`
+	HostEntries     int    `json:"host_entries"`
+	CatchAllEntries int    `json:"catch_all_entries"`
+	Hits            uint64 `json:"hits"`
+	Misses          uint64 `json:"misses"`
+}
+
+// NewDomainCache creates a domain cache holding up to size domains per
+// fact for ttl
+func NewDomainCache(size int, ttl time.Duration) *DomainCache {
+	if size <= 0 {
+		size = 1
+	}
+
+	// lru.New only fails on a non-positive size
+	mx, _ := lru.New[string, domainEntry[*MXLookup]](size)
+	hosts, _ := lru.New[string, domainEntry[*HostLookup]](size)
+	catchAll, _ := lru.New[string, domainEntry[bool]](size)
+
+	return &DomainCache{
+		mx:       mx,
+		hosts:    hosts,
+		catchAll: catchAll,
+		ttl:      ttl,
+	}
+}
+
+// Stats returns a snapshot of the cache state
+func (c *DomainCache) Stats() DomainCacheStats {
+	return DomainCacheStats{
+		MXEntries:       c.mx.Len(),
+		HostEntries:     c.hosts.Len(),
+		CatchAllEntries: c.catchAll.Len(),
+		Hits:            c.hits.Load(),
+		Misses:          c.misses.Load(),
+	}
+}
+
+// Purge removes all cached facts
+func (c *DomainCache) Purge() {
+	c.mx.Purge()
+	c.hosts.Purge()
+	c.catchAll.Purge()
+}
+
+type domainCacheKey struct{}
+
+// WithDomainCache returns a context carrying the given domain cache
+func WithDomainCache(ctx context.Context, cache *DomainCache) context.Context {
+	return context.WithValue(ctx, domainCacheKey{}, cache)
+}
+
+// domainCacheFrom returns the domain cache carried by ctx, if any
+func domainCacheFrom(ctx context.Context) *DomainCache {
+	cache, _ := ctx.Value(domainCacheKey{}).(*DomainCache)
+	return cache
+}
+
+// getEntry returns a cached value if present and not expired
+func getEntry[T any](c *DomainCache, cache *lru.Cache[string, domainEntry[T]], key string) (T, bool) {
+	entry, ok := cache.Get(key)
+	if !ok || time.Now().After(entry.expires) {
+		c.misses.Add(1)
+		var zero T
+		return zero, false
+	}
+	c.hits.Add(1)
+	return entry.value, true
+}
+
+// putEntry caches a value for the cache TTL
+func putEntry[T any](c *DomainCache, cache *lru.Cache[string, domainEntry[T]], key string, value T) {
+	cache.Add(key, domainEntry[T]{value: value, expires: time.Now().Add(c.ttl)})
+}
+
+// shared runs lookup once for all concurrent callers asking for key. A
+// caller whose context is still live redoes a lookup that was cut short by
+// the cancellation of the caller that ran it.
+func shared[T any](ctx context.Context, cache *DomainCache, key string, lookup func() (T, error)) T {
+	if cache == nil {
+		value, _ := lookup()
+		return value
+	}
+
+	value, _, isShared := cache.flight.Do(key, func() (interface{}, error) {
+		value, err := lookup()
+		return sharedValue[T]{value, err}, nil
+	})
+
+	result := value.(sharedValue[T])
+	if isShared && ctx.Err() == nil &&
+		(errors.Is(result.err, context.Canceled) || errors.Is(result.err, context.DeadlineExceeded)) {
+		result.value, _ = lookup()
+	}
+	return result.value
+}
+
+// sharedValue carries a lookup outcome through the singleflight group
+type sharedValue[T any] struct {
+	value T
+	err   error
+}
+
+// cacheable reports whether a lookup outcome can be remembered. Lookups
+// cut short by cancellation or failing temporarily are retried next time.
+func cacheable(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return dnsErr.IsNotFound
+	}
+	return err == nil
+}
+
+// lookupMX resolves and sorts the MX records of a domain, consulting the
+// domain cache carried by ctx first
+func lookupMX(ctx context.Context, resolver *net.Resolver, domain string) *MXLookup {
+	cache := domainCacheFrom(ctx)
+	if cache != nil {
+		if lookup, ok := getEntry(cache, cache.mx, domain); ok {
+			return lookup
+		}
+	}
+
+	return shared(ctx, cache, "mx:"+domain, func() (*MXLookup, error) {
+		records, err := resolver.LookupMX(ctx, domain)
+
+		// Sort by priority
+		sort.Slice(records, func(i, j int) bool {
+			return records[i].Pref < records[j].Pref
+		})
+
+		lookup := &MXLookup{Records: records, Err: err}
+		if err == nil && len(records) == 1 && (records[0].Host == "." || records[0].Host == "") {
+			lookup.NullMX = true
+		}
+
+		if cache != nil && cacheable(ctx, err) {
+			putEntry(cache, cache.mx, domain, lookup)
+		}
+
+		return lookup, err
+	})
+}
+
+// lookupHost resolves the addresses of a host, consulting the domain cache
+// carried by ctx first
+func lookupHost(ctx context.Context, resolver *net.Resolver, host string) *HostLookup {
+	cache := domainCacheFrom(ctx)
+	if cache != nil {
+		if lookup, ok := getEntry(cache, cache.hosts, host); ok {
+			return lookup
+		}
+	}
+
+	return shared(ctx, cache, "host:"+host, func() (*HostLookup, error) {
+		ips, err := resolver.LookupIPAddr(ctx, host)
+		lookup := &HostLookup{IPs: ips, Err: err}
+
+		if cache != nil && cacheable(ctx, err) {
+			putEntry(cache, cache.hosts, host, lookup)
+		}
+
+		return lookup, err
+	})
+}
+
+// cachedCatchAll returns the known catch-all status of a domain
+func cachedCatchAll(ctx context.Context, domain string) (catchAll bool, known bool) {
+	cache := domainCacheFrom(ctx)
+	if cache == nil {
+		return false, false
+	}
+	return getEntry(cache, cache.catchAll, domain)
+}
+
+// storeCatchAll remembers the catch-all status of a domain
+func storeCatchAll(ctx context.Context, domain string, catchAll bool) {
+	if cache := domainCacheFrom(ctx); cache != nil {
+		putEntry(cache, cache.catchAll, domain, catchAll)
+	}
+}
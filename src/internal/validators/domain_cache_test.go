@@ -0,0 +1,265 @@
+package validators
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// fakeDNS answers MX queries from a table, counting the queries per name.
+// Names it doesn't know fail with rcode.
+type fakeDNS struct {
+	mu      sync.Mutex
+	mx      map[string][]string // MX hosts by name, without the trailing dot
+	rcode   map[string]dnsmessage.RCode
+	queries map[string]int
+	delay   time.Duration // Held before each answer
+}
+
+func newFakeDNS() *fakeDNS {
+	return &fakeDNS{
+		mx:      make(map[string][]string),
+		rcode:   make(map[string]dnsmessage.RCode),
+		queries: make(map[string]int),
+	}
+}
+
+// resolver returns a resolver sending its queries to the fake server
+func (d *fakeDNS) resolver() *net.Resolver {
+	return &net.Resolver{PreferGo: true, Dial: d.dial}
+}
+
+// dial connects to the fake server
+func (d *fakeDNS) dial(ctx context.Context, network, address string) (net.Conn, error) {
+	client, server := net.Pipe()
+	go d.serve(server)
+	return client, nil
+}
+
+// count returns the number of queries for name
+func (d *fakeDNS) count(name string) int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.queries[name]
+}
+
+// serve answers the length-prefixed queries the resolver writes to a
+// stream connection
+func (d *fakeDNS) serve(conn net.Conn) {
+	defer conn.Close()
+	for {
+		var length [2]byte
+		if _, err := io.ReadFull(conn, length[:]); err != nil {
+			return
+		}
+		query := make([]byte, binary.BigEndian.Uint16(length[:]))
+		if _, err := io.ReadFull(conn, query); err != nil {
+			return
+		}
+		time.Sleep(d.delay)
+		reply, err := d.answer(query)
+		if err != nil {
+			return
+		}
+		if _, err := conn.Write(binary.BigEndian.AppendUint16(nil, uint16(len(reply)))); err != nil {
+			return
+		}
+		if _, err := conn.Write(reply); err != nil {
+			return
+		}
+	}
+}
+
+// answer builds the reply to a query
+func (d *fakeDNS) answer(query []byte) ([]byte, error) {
+	var msg dnsmessage.Message
+	if err := msg.Unpack(query); err != nil {
+		return nil, err
+	}
+	q := msg.Questions[0]
+	name := strings.TrimSuffix(q.Name.String(), ".")
+
+	d.mu.Lock()
+	hosts, rcode := d.mx[name], d.rcode[name]
+	if q.Type == dnsmessage.TypeMX {
+		d.queries[name]++
+	}
+	d.mu.Unlock()
+
+	reply := dnsmessage.Message{
+		Header:    dnsmessage.Header{ID: msg.ID, Response: true, Authoritative: true, RCode: rcode},
+		Questions: msg.Questions,
+	}
+	if hosts == nil && rcode == dnsmessage.RCodeSuccess {
+		reply.RCode = dnsmessage.RCodeNameError
+	}
+	if q.Type == dnsmessage.TypeMX {
+		for i, host := range hosts {
+			reply.Answers = append(reply.Answers, dnsmessage.Resource{
+				Header: dnsmessage.ResourceHeader{Name: q.Name, Type: dnsmessage.TypeMX, Class: dnsmessage.ClassINET, TTL: 60},
+				Body:   &dnsmessage.MXResource{Pref: uint16(10 * (len(hosts) - i)), MX: dnsmessage.MustNewName(host + ".")},
+			})
+		}
+	}
+	return reply.Pack()
+}
+
+func TestDomainCacheTTL(t *testing.T) {
+	cache := NewDomainCache(10, 50*time.Millisecond)
+	putEntry(cache, cache.mx, "example.com", &MXLookup{})
+
+	if _, ok := getEntry(cache, cache.mx, "example.com"); !ok {
+		t.Fatal("fresh entry missing")
+	}
+	time.Sleep(60 * time.Millisecond)
+	if _, ok := getEntry(cache, cache.mx, "example.com"); ok {
+		t.Error("entry returned past its TTL")
+	}
+	if stats := cache.Stats(); stats.Hits != 1 || stats.Misses != 1 || stats.MXEntries != 1 {
+		t.Errorf("stats = %+v, want 1 hit, 1 miss and 1 MX entry", stats)
+	}
+
+	cache.Purge()
+	if stats := cache.Stats(); stats.MXEntries != 0 {
+		t.Errorf("%d MX entries after Purge", stats.MXEntries)
+	}
+}
+
+func TestCacheable(t *testing.T) {
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		err  error
+		want bool
+	}{
+		{"success", context.Background(), nil, true},
+		{"not found", context.Background(), &net.DNSError{Err: "no such host", IsNotFound: true}, true},
+		{"wrapped not found", context.Background(), fmt.Errorf("lookup: %w", &net.DNSError{IsNotFound: true}), true},
+		{"temporary", context.Background(), &net.DNSError{Err: "server misbehaving", IsTemporary: true}, false},
+		{"timeout", context.Background(), &net.DNSError{Err: "i/o timeout", IsTimeout: true}, false},
+		{"other error", context.Background(), errors.New("refused"), false},
+		{"cancelled", cancelled, nil, false},
+	}
+	for _, tt := range tests {
+		if got := cacheable(tt.ctx, tt.err); got != tt.want {
+			t.Errorf("%s: cacheable = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestLookupMXCache(t *testing.T) {
+	dns := newFakeDNS()
+	dns.mx["example.com"] = []string{"mx2.example.com", "mx1.example.com"}
+	dns.rcode["flaky.example"] = dnsmessage.RCodeServerFailure
+	resolver := dns.resolver()
+
+	ctx := WithDomainCache(context.Background(), NewDomainCache(10, time.Minute))
+
+	for range 3 {
+		lookup := lookupMX(ctx, resolver, "example.com")
+		if lookup.Err != nil || len(lookup.Records) != 2 || lookup.Records[0].Host != "mx1.example.com." {
+			t.Fatalf("lookup = %+v, want both records sorted by preference", lookup)
+		}
+	}
+	if n := dns.count("example.com"); n != 1 {
+		t.Errorf("example.com queried %d times, want 1", n)
+	}
+
+	// Domains that don't exist are remembered
+	for range 3 {
+		var dnsErr *net.DNSError
+		if lookup := lookupMX(ctx, resolver, "missing.example"); !errors.As(lookup.Err, &dnsErr) || !dnsErr.IsNotFound {
+			t.Fatalf("lookup error = %v, want not found", lookup.Err)
+		}
+	}
+	if n := dns.count("missing.example"); n != 1 {
+		t.Errorf("missing.example queried %d times, want 1", n)
+	}
+
+	// Temporary failures are retried
+	for range 3 {
+		if lookup := lookupMX(ctx, resolver, "flaky.example"); lookup.Err == nil {
+			t.Fatal("lookup succeeded for a failing server")
+		}
+	}
+	if n := dns.count("flaky.example"); n < 3 {
+		t.Errorf("flaky.example queried %d times, want every lookup to ask again", n)
+	}
+}
+
+func TestCatchAllCache(t *testing.T) {
+	ctx := WithDomainCache(context.Background(), NewDomainCache(10, 50*time.Millisecond))
+
+	if _, known := cachedCatchAll(ctx, "example.com"); known {
+		t.Fatal("catch-all status known before it was stored")
+	}
+	storeCatchAll(ctx, "example.com", true)
+	storeCatchAll(ctx, "strict.example", false)
+	if catchAll, known := cachedCatchAll(ctx, "example.com"); !known || !catchAll {
+		t.Errorf("example.com catch-all = %v, %v, want true", catchAll, known)
+	}
+	if catchAll, known := cachedCatchAll(ctx, "strict.example"); !known || catchAll {
+		t.Errorf("strict.example catch-all = %v, %v, want a known false", catchAll, known)
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	if _, known := cachedCatchAll(ctx, "example.com"); known {
+		t.Error("catch-all status known past its TTL")
+	}
+
+	// Without a cache nothing is remembered
+	storeCatchAll(context.Background(), "example.com", true)
+	if _, known := cachedCatchAll(context.Background(), "example.com"); known {
+		t.Error("catch-all status known without a cache")
+	}
+}
+
+func TestSharedLookupCancelled(t *testing.T) {
+	cache := NewDomainCache(10, time.Minute)
+
+	first, cancel := context.WithCancel(context.Background())
+	started, release := make(chan struct{}), make(chan struct{})
+	firstDone := make(chan string)
+	go func() {
+		firstDone <- shared(first, cache, "mx:example.com", func() (string, error) {
+			close(started)
+			<-release
+			return "cut short", first.Err()
+		})
+	}()
+	<-started
+
+	// A second caller joins the lookup in flight, then its first caller
+	// gives up
+	var reruns atomic.Int32
+	secondDone := make(chan string)
+	go func() {
+		secondDone <- shared(context.Background(), cache, "mx:example.com", func() (string, error) {
+			reruns.Add(1)
+			return "fresh", nil
+		})
+	}()
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+	close(release)
+
+	if got := <-firstDone; got != "cut short" {
+		t.Errorf("first caller got %q, want its own cancelled lookup", got)
+	}
+	if got := <-secondDone; got != "fresh" || reruns.Load() != 1 {
+		t.Errorf("second caller got %q after %d reruns, want the lookup rerun once", got, reruns.Load())
+	}
+}
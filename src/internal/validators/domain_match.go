@@ -0,0 +1,41 @@
+package validators
+
+import (
+	"strings"
+
+	"golang.org/x/net/publicsuffix"
+)
+
+// matchDomain looks up a domain in a list and returns the list entry
+// it matched. Plain entries match the domain and each parent down to its
+// registrable domain, per the Public Suffix List, so randomized subdomains
+// of a listed service are caught. Wildcard entries such as *.tempmail.dev
+// match any subdomain of theirs, including registrable domains under a
+// public suffix.
+func matchDomain(f interface{ Contains(string) bool }, domain string) (string, bool) {
+	if domain == "" {
+		return "", false
+	}
+
+	// Plain entries stop at the registrable domain so a listed public
+	// suffix can't match every domain under it
+	registrable, err := publicsuffix.EffectiveTLDPlusOne(domain)
+	if err != nil {
+		registrable = domain
+	}
+
+	for parent, subdomain := domain, false; ; subdomain = true {
+		if subdomain && f.Contains("*."+parent) {
+			return "*." + parent, true
+		}
+		if len(parent) >= len(registrable) && f.Contains(parent) {
+			return parent, true
+		}
+
+		dot := strings.IndexByte(parent, '.')
+		if dot < 0 {
+			return "", false
+		}
+		parent = parent[dot+1:]
+	}
+}
@@ -0,0 +1,52 @@
+package validators
+
+import "testing"
+
+// domainSet is a list of domain entries
+type domainSet map[string]bool
+
+func (s domainSet) Contains(item string) bool { return s[item] }
+
+func TestMatchDomain(t *testing.T) {
+	list := domainSet{
+		"tempmail.com":   true,
+		"co.uk":          true,
+		"mailbox.co.uk":  true,
+		"*.tempmail.dev": true,
+		"*.github.io":    true,
+		"com":            true,
+	}
+
+	tests := []struct {
+		domain string
+		entry  string
+	}{
+		{"tempmail.com", "tempmail.com"},
+		{"x7f3.tempmail.com", "tempmail.com"},
+		{"a.b.tempmail.com", "tempmail.com"},
+		{"mailbox.co.uk", "mailbox.co.uk"},
+		{"inbox.mailbox.co.uk", "mailbox.co.uk"},
+		// Plain entries stop at the registrable domain, so a listed public
+		// suffix matches nothing under it
+		{"example.co.uk", ""},
+		{"example.com", ""},
+		{"co.uk", "co.uk"},
+		// Wildcards match subdomains only, including registrable domains
+		// under a public suffix
+		{"x7f3.tempmail.dev", "*.tempmail.dev"},
+		{"a.b.tempmail.dev", "*.tempmail.dev"},
+		{"tempmail.dev", ""},
+		{"someone.github.io", "*.github.io"},
+		{"github.io", ""},
+		{"tempmail.com.example.org", ""},
+		{"nottempmail.com", ""},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		entry, ok := matchDomain(list, tt.domain)
+		if entry != tt.entry || ok != (tt.entry != "") {
+			t.Errorf("matchDomain(%q) = %q, %v, want %q", tt.domain, entry, ok, tt.entry)
+		}
+	}
+}
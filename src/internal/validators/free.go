@@ -3,7 +3,7 @@ package validators
 import (
 	"context"
 	"fmt"
-	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/wizenheimer/bloombox/internal/filter"
@@ -12,49 +12,48 @@ import (
 
 // FreeValidator checks against free email providers
 type FreeValidator struct {
-	filter  filter.Filter
-	enabled bool
+	list     *listSource[*listData]
+	disabled atomic.Bool
 }
 
 // NewFreeValidator creates a new free email validator
-func NewFreeValidator(filename string, falsePositiveRate float64) (Validator, error) {
-	l := loader.NewFileLoader()
-	domains, err := l.LoadFromFile(filename)
-	if err != nil {
-		return nil, fmt.Errorf("failed to load free emails file: %w", err)
-	}
-
-	var f filter.Filter
-	if falsePositiveRate == 0 {
-		f = filter.NewMapFilter()
-	} else {
-		f = filter.NewCuckooFilter(len(domains), falsePositiveRate)
-	}
-
-	for _, domain := range domains {
-		domain = strings.ToLower(strings.TrimSpace(domain))
-		if domain != "" {
-			f.Add(domain)
+func NewFreeValidator(filename string, falsePositiveRate float64, filterType filter.Type, snapshotFile string, maxErrorRate, reloadMaxErrorRate float64) (Validator, error) {
+	list, err := newListDataSource(filename, reloadMaxErrorRate, func() (*listData, int, error) {
+		data, err := loadList(filename, snapshotFile, filterType, falsePositiveRate, 0, maxErrorRate, normalizeDomainEntry)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to load free emails file: %w", err)
 		}
+		return data, data.filter.Size(), nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return &FreeValidator{
-		filter:  f,
-		enabled: true,
+		list: list,
 	}, nil
 }
 
 func (v *FreeValidator) Name() string { return "free" }
 
-func (v *FreeValidator) IsEnabled() bool { return v.enabled }
+func (v *FreeValidator) IsEnabled() bool { return !v.disabled.Load() }
 
-func (v *FreeValidator) SetEnabled(enabled bool) { v.enabled = enabled }
+func (v *FreeValidator) SetEnabled(enabled bool) { v.disabled.Store(!enabled) }
+
+func (v *FreeValidator) FilterStats() filter.Stats { return v.list.Load().filter.Stats() }
+
+func (v *FreeValidator) LoadReport() loader.LoadReport { return v.list.Load().report }
+
+func (v *FreeValidator) Source() string { return v.list.Source() }
+
+func (v *FreeValidator) Reload() (int, error) { return v.list.Reload() }
 
 func (v *FreeValidator) Validate(ctx context.Context, email string) *ValidationResult {
 	start := time.Now()
 
 	domain := extractDomain(email)
-	isFree := v.filter.Contains(domain)
+	list := v.list.Load()
+	entry, isFree := matchDomain(list, domain)
 
 	result := &ValidationResult{
 		Valid: !isFree,
@@ -65,12 +64,16 @@ func (v *FreeValidator) Validate(ctx context.Context, email string) *ValidationR
 				return "Not a free email provider"
 			}
 		}(),
+		Code: listCode(isFree, CodeListFree),
 		Details: map[string]interface{}{
 			"domain":  domain,
 			"is_free": isFree,
 		},
 		Duration: time.Since(start),
 	}
+	if isFree {
+		result.Details["matched_entry"] = list.matchedEntry(entry)
+	}
 
 	return result
 }
@@ -6,14 +6,15 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+	"sync/atomic"
 	"time"
 )
 
 // GravatarValidator checks if email has a Gravatar account
 type GravatarValidator struct {
-	timeout time.Duration
-	client  *http.Client
-	enabled bool
+	timeout  time.Duration
+	client   *http.Client
+	disabled atomic.Bool
 }
 
 // NewGravatarValidator creates a new Gravatar validator
@@ -23,15 +24,14 @@ func NewGravatarValidator(timeout time.Duration) Validator {
 		client: &http.Client{
 			Timeout: timeout,
 		},
-		enabled: true,
 	}
 }
 
 func (v *GravatarValidator) Name() string { return "gravatar" }
 
-func (v *GravatarValidator) IsEnabled() bool { return v.enabled }
+func (v *GravatarValidator) IsEnabled() bool { return !v.disabled.Load() }
 
-func (v *GravatarValidator) SetEnabled(enabled bool) { v.enabled = enabled }
+func (v *GravatarValidator) SetEnabled(enabled bool) { v.disabled.Store(!enabled) }
 
 func (v *GravatarValidator) Validate(ctx context.Context, email string) *ValidationResult {
 	start := time.Now()
@@ -51,6 +51,7 @@ func (v *GravatarValidator) Validate(ctx context.Context, email string) *Validat
 	if err != nil {
 		result.Valid = false
 		result.Message = "Failed to create request"
+		result.Code = CodeGravatarRequestFailed
 		result.Error = err.Error()
 		result.Duration = time.Since(start)
 		return result
@@ -60,6 +61,7 @@ func (v *GravatarValidator) Validate(ctx context.Context, email string) *Validat
 	if err != nil {
 		result.Valid = false
 		result.Message = "Failed to check Gravatar"
+		result.Code = CodeGravatarRequestFailed
 		result.Error = err.Error()
 		result.Duration = time.Since(start)
 		return result
@@ -75,6 +77,10 @@ func (v *GravatarValidator) Validate(ctx context.Context, email string) *Validat
 		}
 		return "No Gravatar account found"
 	}()
+	result.Code = CodeGravatarNotFound
+	if hasGravatar {
+		result.Code = CodeGravatarFound
+	}
 	result.Details["gravatar_url"] = gravatarURL
 	result.Details["gravatar_hash"] = hash
 	result.Details["has_gravatar"] = hasGravatar
@@ -0,0 +1,86 @@
+package validators
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/wizenheimer/bloombox/internal/filter"
+	"github.com/wizenheimer/bloombox/internal/loader"
+)
+
+// listData is a list filter with the metadata of the entries of a CSV or
+// JSONL list file, kept beside the filter by key, and the report of the
+// load. Plain list files have no metadata.
+type listData struct {
+	filter filter.Filter
+	meta   map[string]loader.ListEntry
+	report loader.LoadReport
+}
+
+// newListDataSource builds the source of a list validator. Reloads are
+// refused when every entry of the new file was rejected, or more than
+// reloadMaxErrorRate of them, so a garbage file or an error page served in
+// place of a list never replaces a good one, however lenient the first
+// load was.
+func newListDataSource(filename string, reloadMaxErrorRate float64, build func() (*listData, int, error)) (*listSource[*listData], error) {
+	list, err := newListSource(filename, build)
+	if err != nil {
+		return nil, err
+	}
+	list.accept = func(data *listData) error {
+		report := data.report
+		if report.Loaded == 0 && report.Rejected > 0 {
+			return fmt.Errorf("%s: all %d entries rejected, keeping the current list", filename, report.Rejected)
+		}
+		if err := report.CheckErrorRate(reloadMaxErrorRate); err != nil {
+			return fmt.Errorf("%w, keeping the current list", err)
+		}
+		return nil
+	}
+	return list, nil
+}
+
+// Contains checks if key is listed by an entry that hasn't expired
+func (ld *listData) Contains(key string) bool {
+	if !ld.filter.Contains(key) {
+		return false
+	}
+	entry, ok := ld.meta[key]
+	return !ok || !entry.Expired(time.Now())
+}
+
+// matchedEntry describes the entry listing key, for the matched_entry
+// detail that explains why an address was flagged
+func (ld *listData) matchedEntry(key string) map[string]interface{} {
+	details := map[string]interface{}{"value": key}
+
+	entry, ok := ld.meta[key]
+	if !ok {
+		return details
+	}
+	for name, value := range map[string]string{
+		"category": entry.Category,
+		"source":   entry.Source,
+		"reason":   entry.Reason,
+		"added":    formatEntryDate(entry.Added),
+		"expires":  formatEntryDate(entry.Expires),
+	} {
+		if value != "" {
+			details[name] = value
+		}
+	}
+	return details
+}
+
+// formatEntryDate formats an entry date as it is usually written, as a
+// plain date unless it has a time of day
+func formatEntryDate(t time.Time) string {
+	switch {
+	case t.IsZero():
+		return ""
+	case t.Equal(t.Truncate(24 * time.Hour)):
+		return t.Format(time.DateOnly)
+	default:
+		return t.Format(time.RFC3339)
+	}
+}
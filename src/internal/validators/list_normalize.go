@@ -0,0 +1,48 @@
+package validators
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/wizenheimer/bloombox/internal/normalizer"
+)
+
+// normalizeDomainEntry cleans up a domain list entry: it strips a leading
+// @ and a trailing dot, converts the domain to its IDNA A-label form and
+// rejects anything that isn't a fully qualified host name. A leading *.
+// marks a wildcard entry and is kept.
+func normalizeDomainEntry(entry string) (string, error) {
+	domain := strings.ToLower(strings.TrimSpace(entry))
+	if strings.Contains(domain, "://") || strings.Contains(domain, "/") {
+		return "", errors.New("looks like a URL, expected a domain")
+	}
+	if strings.LastIndex(domain, "@") > 0 {
+		return "", errors.New("looks like an email address, expected a domain")
+	}
+
+	domain = strings.TrimPrefix(domain, "@")
+	wildcard := strings.HasPrefix(domain, "*.")
+	domain = strings.TrimPrefix(domain, "*.")
+	if strings.HasPrefix(domain, "[") {
+		return "", errors.New("address literals are not supported, expected a domain")
+	}
+
+	addr := &Address{}
+	if err := parseDomain(addr, domain); err != nil {
+		return "", err
+	}
+	if wildcard {
+		return "*." + addr.ASCIIDomain, nil
+	}
+	return addr.ASCIIDomain, nil
+}
+
+// normalizeEmailEntry validates an email list entry and returns its
+// canonical form, with the domain in IDNA A-label form
+func normalizeEmailEntry(entry string) (string, error) {
+	addr, err := ParseAddress(strings.ToLower(strings.TrimSpace(entry)))
+	if err != nil {
+		return "", err
+	}
+	return normalizer.Canonical(addr.ASCII()), nil
+}
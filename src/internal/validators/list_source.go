@@ -0,0 +1,62 @@
+package validators
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// listSource holds what a list validator builds from its source file.
+// Reload builds a new value off to the side and swaps it in atomically, so
+// Validate calls keep using the current value without blocking.
+type listSource[T any] struct {
+	filename string
+	build    func() (T, int, error) // Builds the value and counts its items
+	accept   func(T) error          // Vets a reloaded value before it is swapped in, nil accepts any
+	current  atomic.Pointer[listValue[T]]
+	mu       sync.Mutex // Serializes reloads
+}
+
+// listValue is a built value and its item count
+type listValue[T any] struct {
+	value T
+	count int
+}
+
+// newListSource builds the initial value of a list source
+func newListSource[T any](filename string, build func() (T, int, error)) (*listSource[T], error) {
+	ls := &listSource[T]{filename: filename, build: build}
+	if _, err := ls.Reload(); err != nil {
+		return nil, err
+	}
+	return ls, nil
+}
+
+// Load returns the current value
+func (ls *listSource[T]) Load() T {
+	return ls.current.Load().value
+}
+
+// Source returns the file the value is built from
+func (ls *listSource[T]) Source() string {
+	return ls.filename
+}
+
+// Reload rebuilds the value from the source file and returns its item
+// count. The current value is kept when the build fails or accept refuses
+// the new value. A file without items clears the list.
+func (ls *listSource[T]) Reload() (int, error) {
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+
+	value, count, err := ls.build()
+	if err != nil {
+		return 0, err
+	}
+	if ls.current.Load() != nil && ls.accept != nil {
+		if err := ls.accept(value); err != nil {
+			return 0, err
+		}
+	}
+	ls.current.Store(&listValue[T]{value: value, count: count})
+	return count, nil
+}
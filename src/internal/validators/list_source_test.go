@@ -0,0 +1,132 @@
+package validators
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestListSourceReload(t *testing.T) {
+	var builds atomic.Int32
+	var fail atomic.Bool
+	ls, err := newListSource("list.txt", func() ([]int, int, error) {
+		if fail.Load() {
+			return nil, 0, errors.New("bad list")
+		}
+		n := int(builds.Add(1))
+		value := make([]int, n)
+		for i := range value {
+			value[i] = n
+		}
+		return value, n, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Readers only ever see a complete value while reloads swap it
+	var wg sync.WaitGroup
+	stop := make(chan struct{})
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				value := ls.Load()
+				for _, v := range value {
+					if v != len(value) {
+						t.Errorf("loaded a partly built value %v", value)
+						return
+					}
+				}
+			}
+		}()
+	}
+	for range 100 {
+		if _, err := ls.Reload(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	close(stop)
+	wg.Wait()
+
+	// A failed build keeps the current value
+	fail.Store(true)
+	before := len(ls.Load())
+	if _, err := ls.Reload(); err == nil {
+		t.Fatal("Reload succeeded with a failing build")
+	}
+	if got := len(ls.Load()); got != before {
+		t.Errorf("value has %d items after a failed reload, want %d", got, before)
+	}
+}
+
+func TestListValidatorReload(t *testing.T) {
+	dir := t.TempDir()
+	list := writeList(t, dir, "disposable.txt", "throwaway.example")
+	v, err := NewDisposableValidator(list, 0.01, "", "", 1, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	writeList(t, dir, "disposable.txt", "burner.example")
+	if count, err := v.(ReloadableValidator).Reload(); err != nil || count != 1 {
+		t.Fatalf("Reload = %d, %v, want 1 item", count, err)
+	}
+	if !v.Validate(ctx, "user@throwaway.example").Valid {
+		t.Error("removed domain still flagged after reload")
+	}
+	if v.Validate(ctx, "user@burner.example").Valid {
+		t.Error("added domain not flagged after reload")
+	}
+
+	// An emptied file clears the list
+	writeList(t, dir, "disposable.txt")
+	if _, err := v.(ReloadableValidator).Reload(); err != nil {
+		t.Fatal(err)
+	}
+	if !v.Validate(ctx, "user@burner.example").Valid {
+		t.Error("domain flagged after the list was emptied")
+	}
+}
+
+func TestListValidatorReloadRefused(t *testing.T) {
+	tests := []struct {
+		name    string
+		lines   []string
+		wantErr string
+	}{
+		{"error page", []string{"<html>", "<body>502 Bad Gateway</body>", "</html>"}, "all 3 entries rejected"},
+		{"too many rejected", []string{"burner.example", "not a domain", "http://x.example/"}, "2 of 3 entries rejected"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			list := writeList(t, dir, "disposable.txt", "throwaway.example", "not a domain")
+			// The first load is lenient, reloads are held to the rate
+			v, err := NewDisposableValidator(list, 0.01, "", "", 1, 0.5)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			writeList(t, dir, "disposable.txt", tt.lines...)
+			_, err = v.(ReloadableValidator).Reload()
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Reload error = %v, want %q", err, tt.wantErr)
+			}
+			if v.Validate(context.Background(), "user@throwaway.example").Valid {
+				t.Error("current list dropped after a refused reload")
+			}
+		})
+	}
+}
@@ -0,0 +1,110 @@
+package validators
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/wizenheimer/bloombox/internal/filter"
+)
+
+// testDomains returns n distinct domains
+func testDomains(prefix string, n int) []string {
+	domains := make([]string, n)
+	for i := range domains {
+		domains[i] = fmt.Sprintf("%s-%d.example", prefix, i)
+	}
+	return domains
+}
+
+func TestLoadListSnapshot(t *testing.T) {
+	dir := t.TempDir()
+	list := writeList(t, dir, "list.txt", append(testDomains("listed", 500), "not a domain")...)
+	snapshot := filepath.Join(dir, "list.snapshot")
+
+	load := func(filterType filter.Type, falsePositiveRate float64) *listData {
+		t.Helper()
+		data, err := loadList(list, snapshot, filterType, falsePositiveRate, 0, 1, normalizeDomainEntry)
+		if err != nil {
+			t.Fatalf("loadList: %v", err)
+		}
+		return data
+	}
+
+	first := load("", 0.01)
+	if first.report.Snapshot {
+		t.Fatal("first load restored a snapshot that didn't exist")
+	}
+	if _, err := os.Stat(snapshot); err != nil {
+		t.Fatalf("no snapshot written: %v", err)
+	}
+
+	second := load("", 0.01)
+	if !second.report.Snapshot {
+		t.Error("second load didn't restore the snapshot")
+	}
+	// A snapshot hit reports the counts of the load that wrote it
+	first.report.Errors, first.report.Snapshot = nil, true
+	if fmt.Sprint(second.report) != fmt.Sprint(first.report) {
+		t.Errorf("snapshot report = %+v, want %+v", second.report, first.report)
+	}
+	for _, domain := range testDomains("listed", 500) {
+		if !second.filter.Contains(domain) {
+			t.Fatalf("restored filter is missing %s", domain)
+		}
+	}
+
+	// Other settings or a changed list rebuild the snapshot
+	if load(filter.TypeBloom, 0.01).report.Snapshot {
+		t.Error("snapshot restored for another filter type")
+	}
+	if load(filter.TypeBloom, 0.001).report.Snapshot {
+		t.Error("snapshot restored for another false positive rate")
+	}
+	writeList(t, dir, "list.txt", "other.example")
+	rebuilt := load(filter.TypeBloom, 0.001)
+	if rebuilt.report.Snapshot || !rebuilt.filter.Contains("other.example") {
+		t.Error("snapshot restored for a changed list")
+	}
+}
+
+func TestLoadListSnapshotErrorRate(t *testing.T) {
+	dir := t.TempDir()
+	list := writeList(t, dir, "list.txt", "a.example", "b.example", "c.example", "not a domain")
+	snapshot := filepath.Join(dir, "list.snapshot")
+
+	if _, err := loadList(list, snapshot, "", 0.01, 0, 1, normalizeDomainEntry); err != nil {
+		t.Fatalf("lenient loadList: %v", err)
+	}
+	// A stricter load can't skip the check through the snapshot
+	_, err := loadList(list, snapshot, "", 0.01, 0, 0.1, normalizeDomainEntry)
+	if err == nil || !strings.Contains(err.Error(), "1 of 4 entries rejected") {
+		t.Errorf("strict loadList error = %v, want the rejected entry reported", err)
+	}
+}
+
+func TestDisposableValidatorNoFalsePositives(t *testing.T) {
+	dir := t.TempDir()
+	listed := testDomains("throwaway", 5000)
+	list := writeList(t, dir, "disposable.txt", listed...)
+
+	// A loose false positive rate still gives none with the default filter
+	v, err := NewDisposableValidator(list, 0.2, "", "", 1, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	for _, domain := range listed {
+		if v.Validate(ctx, "user@"+domain).Valid {
+			t.Fatalf("%s not flagged as disposable", domain)
+		}
+	}
+	for _, domain := range testDomains("company", 20000) {
+		if !v.Validate(ctx, "user@"+domain).Valid {
+			t.Fatalf("%s flagged as disposable", domain)
+		}
+	}
+}
@@ -4,7 +4,7 @@ package validators
 import (
 	"context"
 	"net"
-	"sort"
+	"sync/atomic"
 	"time"
 )
 
@@ -12,7 +12,7 @@ import (
 type MXValidator struct {
 	timeout  time.Duration
 	dialFunc DialFunc
-	enabled  bool
+	disabled atomic.Bool
 }
 
 // NewMXValidator creates a new MX validator
@@ -24,15 +24,14 @@ func NewMXValidator(timeout time.Duration, dialFunc DialFunc) Validator {
 	return &MXValidator{
 		timeout:  timeout,
 		dialFunc: dialFunc,
-		enabled:  true,
 	}
 }
 
 func (v *MXValidator) Name() string { return "mx" }
 
-func (v *MXValidator) IsEnabled() bool { return v.enabled }
+func (v *MXValidator) IsEnabled() bool { return !v.disabled.Load() }
 
-func (v *MXValidator) SetEnabled(enabled bool) { v.enabled = enabled }
+func (v *MXValidator) SetEnabled(enabled bool) { v.disabled.Store(!enabled) }
 
 func (v *MXValidator) Validate(ctx context.Context, email string) *ValidationResult {
 	start := time.Now()
@@ -44,34 +43,43 @@ func (v *MXValidator) Validate(ctx context.Context, email string) *ValidationRes
 		Dial:     v.dialFunc,
 	}
 
-	mxRecords, err := resolver.LookupMX(ctx, domain)
+	lookup := lookupMX(ctx, resolver, domain)
+	mxRecords, err := lookup.Records, lookup.Err
 
 	result := &ValidationResult{
-		Details:  make(map[string]interface{}),
-		Duration: time.Since(start),
+		Details: make(map[string]interface{}),
 	}
 
-	if err != nil {
+	switch {
+	case lookup.NullMX:
+		result.Valid = false
+		result.Message = "Domain does not accept email (null MX)"
+		result.Code = CodeMXNull
+		result.Details["null_mx"] = true
+
+	case err != nil || len(mxRecords) == 0:
 		// Try A record as fallback
-		ips, aErr := resolver.LookupIPAddr(ctx, domain)
-		if aErr != nil {
+		hosts := lookupHost(ctx, resolver, domain)
+		if hosts.Err != nil {
 			result.Valid = false
 			result.Message = "No MX or A records found"
+			result.Code = CodeMXNoRecords
+			if err == nil {
+				err = hosts.Err
+			}
 			result.Error = err.Error()
 		} else {
 			result.Valid = true
 			result.Message = "No MX record, but domain has A record (implicit MX)"
+			result.Code = CodeMXImplicit
 			result.Details["implicit_mx"] = true
-			result.Details["a_records"] = len(ips)
+			result.Details["a_records"] = len(hosts.IPs)
 		}
-	} else {
+
+	default:
 		result.Valid = true
 		result.Message = "Valid MX records found"
-
-		// Sort MX records by priority
-		sort.Slice(mxRecords, func(i, j int) bool {
-			return mxRecords[i].Pref < mxRecords[j].Pref
-		})
+		result.Code = CodeMXFound
 
 		mxDetails := make([]MXRecord, len(mxRecords))
 		for i, mx := range mxRecords {
@@ -81,8 +89,8 @@ func (v *MXValidator) Validate(ctx context.Context, email string) *ValidationRes
 			}
 
 			// Try to resolve IP for the MX host
-			if ips, err := resolver.LookupIPAddr(ctx, mx.Host); err == nil && len(ips) > 0 {
-				mxDetails[i].IP = ips[0].IP.String()
+			if hosts := lookupHost(ctx, resolver, mx.Host); hosts.Err == nil && len(hosts.IPs) > 0 {
+				mxDetails[i].IP = hosts.IPs[0].IP.String()
 			}
 		}
 
@@ -91,5 +99,6 @@ func (v *MXValidator) Validate(ctx context.Context, email string) *ValidationRes
 		result.Details["primary_mx"] = mxDetails[0].Host
 	}
 
+	result.Duration = time.Since(start)
 	return result
 }
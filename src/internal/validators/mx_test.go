@@ -0,0 +1,35 @@
+package validators
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestMXValidatorSharedLookup(t *testing.T) {
+	dns := newFakeDNS()
+	dns.mx["example.com"] = []string{"mx.example.com"}
+	dns.delay = 50 * time.Millisecond
+	v := NewMXValidator(time.Second, dns.dial)
+
+	// Concurrent checks of addresses at one domain resolve it once, the
+	// cache only helps the checks that come after
+	ctx := WithDomainCache(context.Background(), NewDomainCache(10, time.Minute))
+	var wg sync.WaitGroup
+	for i := range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if result := v.Validate(ctx, fmt.Sprintf("user%d@example.com", i)); !result.Valid {
+				t.Errorf("result = %+v, want valid MX records", result)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if n := dns.count("example.com"); n != 1 {
+		t.Errorf("example.com queried %d times by concurrent checks, want 1", n)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/wizenheimer/bloombox/internal/loader"
@@ -11,43 +12,52 @@ import (
 
 // RoleValidator checks for role-based email addresses
 type RoleValidator struct {
-	roleEmails map[string]bool
-	enabled    bool
+	list     *listSource[map[string]bool]
+	disabled atomic.Bool
 }
 
 // NewRoleValidator creates a new role-based email validator
 func NewRoleValidator(filename string) (Validator, error) {
-	l := loader.NewFileLoader()
-	roles, err := l.LoadFromFile(filename)
-	if err != nil {
-		return nil, fmt.Errorf("failed to load role emails file: %w", err)
-	}
+	list, err := newListSource(filename, func() (map[string]bool, int, error) {
+		l := loader.NewFileLoader()
+		roles, err := l.LoadFromFile(filename)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to load role emails file: %w", err)
+		}
 
-	roleMap := make(map[string]bool)
-	for _, role := range roles {
-		role = strings.ToLower(strings.TrimSpace(role))
-		if role != "" {
-			roleMap[role] = true
+		roleMap := make(map[string]bool)
+		for _, role := range roles {
+			role = strings.ToLower(strings.TrimSpace(role))
+			if role != "" {
+				roleMap[role] = true
+			}
 		}
+		return roleMap, len(roleMap), nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return &RoleValidator{
-		roleEmails: roleMap,
-		enabled:    true,
+		list: list,
 	}, nil
 }
 
 func (v *RoleValidator) Name() string { return "role" }
 
-func (v *RoleValidator) IsEnabled() bool { return v.enabled }
+func (v *RoleValidator) IsEnabled() bool { return !v.disabled.Load() }
+
+func (v *RoleValidator) SetEnabled(enabled bool) { v.disabled.Store(!enabled) }
+
+func (v *RoleValidator) Source() string { return v.list.Source() }
 
-func (v *RoleValidator) SetEnabled(enabled bool) { v.enabled = enabled }
+func (v *RoleValidator) Reload() (int, error) { return v.list.Reload() }
 
 func (v *RoleValidator) Validate(ctx context.Context, email string) *ValidationResult {
 	start := time.Now()
 
 	localPart := extractLocalPart(email)
-	isRole := v.roleEmails[localPart]
+	isRole := v.list.Load()[localPart]
 
 	result := &ValidationResult{
 		Valid: !isRole,
@@ -58,6 +68,7 @@ func (v *RoleValidator) Validate(ctx context.Context, email string) *ValidationR
 				return "Not a role-based email"
 			}
 		}(),
+		Code: listCode(isRole, CodeListRole),
 		Details: map[string]interface{}{
 			"local_part": localPart,
 			"is_role":    isRole,
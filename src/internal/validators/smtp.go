@@ -2,11 +2,13 @@ package validators
 
 import (
 	"context"
+	"crypto/rand"
 	"fmt"
 	"net"
 	"net/smtp"
 	"net/textproto"
-	"sort"
+	"strings"
+	"sync/atomic"
 	"time"
 )
 
@@ -22,8 +24,8 @@ type SMTPConfig struct {
 
 // SMTPValidator validates email addresses via SMTP
 type SMTPValidator struct {
-	config  *SMTPConfig
-	enabled bool
+	config   *SMTPConfig
+	disabled atomic.Bool
 }
 
 // NewSMTPValidator creates a new SMTP validator
@@ -33,16 +35,15 @@ func NewSMTPValidator(config *SMTPConfig) Validator {
 	}
 
 	return &SMTPValidator{
-		config:  config,
-		enabled: true,
+		config: config,
 	}
 }
 
 func (v *SMTPValidator) Name() string { return "smtp" }
 
-func (v *SMTPValidator) IsEnabled() bool { return v.enabled }
+func (v *SMTPValidator) IsEnabled() bool { return !v.disabled.Load() }
 
-func (v *SMTPValidator) SetEnabled(enabled bool) { v.enabled = enabled }
+func (v *SMTPValidator) SetEnabled(enabled bool) { v.disabled.Store(!enabled) }
 
 func (v *SMTPValidator) Validate(ctx context.Context, email string) *ValidationResult {
 	start := time.Now()
@@ -54,11 +55,21 @@ func (v *SMTPValidator) Validate(ctx context.Context, email string) *ValidationR
 	}
 
 	// Get MX records first
-	mxRecords, err := v.getMXRecords(ctx, domain)
-	if err != nil {
+	lookup := v.getMXRecords(ctx, domain)
+	mxRecords := lookup.Records
+	if lookup.Err != nil {
 		result.Valid = false
 		result.Message = "Could not resolve MX records"
-		result.Error = err.Error()
+		result.Code = CodeSMTPMXLookupFailed
+		result.Error = lookup.Err.Error()
+		result.Duration = time.Since(start)
+		return result
+	}
+
+	if lookup.NullMX {
+		result.Valid = false
+		result.Message = "Domain does not accept email (null MX)"
+		result.Code = CodeSMTPNullMX
 		result.Duration = time.Since(start)
 		return result
 	}
@@ -66,15 +77,23 @@ func (v *SMTPValidator) Validate(ctx context.Context, email string) *ValidationR
 	if len(mxRecords) == 0 {
 		result.Valid = false
 		result.Message = "No MX records found"
+		result.Code = CodeSMTPNoMX
 		result.Duration = time.Since(start)
 		return result
 	}
 
+	// Address the recipient with the A-label form of its domain
+	recipient := email
+	if at := strings.LastIndex(email, "@"); at >= 0 {
+		recipient = email[:at] + "@" + domain
+	}
+
 	// Try SMTP validation with the primary MX server
-	smtpResult := v.validateViaSMTP(ctx, email, mxRecords[0])
+	smtpResult, code := v.validateViaSMTP(ctx, recipient, domain, mxRecords[0])
 
 	result.Valid = smtpResult.CanReceive
 	result.Message = smtpResult.Message
+	result.Code = code
 	result.Details["smtp_response"] = smtpResult
 	result.Details["mx_host"] = mxRecords[0].Host
 	result.Duration = time.Since(start)
@@ -82,28 +101,20 @@ func (v *SMTPValidator) Validate(ctx context.Context, email string) *ValidationR
 	return result
 }
 
-// getMXRecords retrieves and sorts MX records
-func (v *SMTPValidator) getMXRecords(ctx context.Context, domain string) ([]*net.MX, error) {
+// getMXRecords retrieves sorted MX records, reusing cached lookups of the
+// domain when available
+func (v *SMTPValidator) getMXRecords(ctx context.Context, domain string) *MXLookup {
 	resolver := &net.Resolver{
 		PreferGo: true,
 		Dial:     v.config.DialFunc,
 	}
 
-	mxRecords, err := resolver.LookupMX(ctx, domain)
-	if err != nil {
-		return nil, err
-	}
-
-	// Sort by priority
-	sort.Slice(mxRecords, func(i, j int) bool {
-		return mxRecords[i].Pref < mxRecords[j].Pref
-	})
-
-	return mxRecords, nil
+	return lookupMX(ctx, resolver, domain)
 }
 
-// validateViaSMTP performs SMTP validation
-func (v *SMTPValidator) validateViaSMTP(ctx context.Context, email string, mx *net.MX) *SMTPResponse {
+// validateViaSMTP performs SMTP validation and returns the response with
+// its reason code
+func (v *SMTPValidator) validateViaSMTP(ctx context.Context, email, domain string, mx *net.MX) (*SMTPResponse, string) {
 	response := &SMTPResponse{}
 
 	// Connect to MX server
@@ -111,15 +122,23 @@ func (v *SMTPValidator) validateViaSMTP(ctx context.Context, email string, mx *n
 	if err != nil {
 		response.Code = 0
 		response.Message = fmt.Sprintf("Connection failed: %v", err)
-		return response
+		return response, CodeSMTPConnectionFailed
 	}
 	defer conn.Close()
 
+	// Bound the SMTP dialogue by the context: apply its deadline and
+	// drop the connection as soon as it is cancelled
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
 	client, err := smtp.NewClient(conn, mx.Host)
 	if err != nil {
 		response.Code = 0
 		response.Message = fmt.Sprintf("SMTP client creation failed: %v", err)
-		return response
+		return response, CodeSMTPConnectionFailed
 	}
 	defer client.Quit()
 
@@ -127,32 +146,46 @@ func (v *SMTPValidator) validateViaSMTP(ctx context.Context, email string, mx *n
 	if err := client.Hello(v.config.FromDomain); err != nil {
 		response.Code = 0
 		response.Message = fmt.Sprintf("HELO failed: %v", err)
-		return response
+		return response, CodeSMTPProtocolError
 	}
 
 	// MAIL FROM
 	if err := client.Mail(v.config.FromEmail); err != nil {
 		response.Code = 0
 		response.Message = fmt.Sprintf("MAIL FROM failed: %v", err)
-		return response
+		return response, CodeSMTPProtocolError
+	}
+
+	// Internationalized local parts can only be delivered with SMTPUTF8
+	response.SMTPUTF8, _ = client.Extension("SMTPUTF8")
+	if !isASCII(email) && !response.SMTPUTF8 {
+		response.Message = "Server does not support SMTPUTF8, required by this address"
+		return response, CodeSMTPSMTPUTF8Unsupported
 	}
 
 	// Try VRFY command if enabled
 	if v.config.EnableVRFY {
 		if vrfyResult := v.tryVRFY(client, email); vrfyResult != nil {
-			return vrfyResult
+			return vrfyResult, CodeSMTPMailboxExists
 		}
 	}
 
 	// Try RCPT TO if enabled
 	if v.config.EnableRCPT {
-		return v.tryRCPT(client, email)
+		rcpt, code := v.tryRCPT(client, email)
+		rcpt.SMTPUTF8 = response.SMTPUTF8
+		if rcpt.IsMailbox && v.isCatchAll(ctx, client, domain) {
+			rcpt.IsCatchAll = true
+			rcpt.Message = "Recipient accepted, but domain accepts all recipients (catch-all)"
+			code = CodeSMTPCatchAll
+		}
+		return rcpt, code
 	}
 
 	response.Code = 250
 	response.Message = "SMTP connection successful"
 	response.CanReceive = true
-	return response
+	return response, CodeSMTPConnected
 }
 
 // tryVRFY attempts VRFY command
@@ -162,9 +195,36 @@ func (v *SMTPValidator) tryVRFY(_ *smtp.Client, _ string) *SMTPResponse {
 	return nil
 }
 
+// isCatchAll reports whether the domain accepts mail for any recipient, by
+// probing a random address once per domain cache TTL
+func (v *SMTPValidator) isCatchAll(ctx context.Context, client *smtp.Client, domain string) bool {
+	if catchAll, known := cachedCatchAll(ctx, domain); known {
+		return catchAll
+	}
+
+	token := make([]byte, 8)
+	if _, err := rand.Read(token); err != nil {
+		return false
+	}
+	probe := fmt.Sprintf("bloombox-%x@%s", token, domain)
+
+	err := client.Rcpt(probe)
+	if err == nil {
+		storeCatchAll(ctx, domain, true)
+		return true
+	}
+
+	// Only a permanent rejection tells the domain is not catch-all
+	if smtpErr, ok := err.(*textproto.Error); ok && smtpErr.Code >= 500 {
+		storeCatchAll(ctx, domain, false)
+	}
+	return false
+}
+
 // tryRCPT attempts RCPT TO command
-func (v *SMTPValidator) tryRCPT(client *smtp.Client, email string) *SMTPResponse {
+func (v *SMTPValidator) tryRCPT(client *smtp.Client, email string) (*SMTPResponse, string) {
 	response := &SMTPResponse{}
+	code := CodeSMTPRejected
 
 	err := client.Rcpt(email)
 	if err != nil {
@@ -177,15 +237,19 @@ func (v *SMTPValidator) tryRCPT(client *smtp.Client, email string) *SMTPResponse
 			case smtpErr.Code >= 200 && smtpErr.Code < 300:
 				response.CanReceive = true
 				response.IsMailbox = true
+				code = CodeSMTPMailboxExists
 			case smtpErr.Code == 550:
 				response.CanReceive = false
 				response.Message = "Mailbox does not exist"
+				code = CodeSMTPMailboxNotFound
 			case smtpErr.Code == 551:
 				response.CanReceive = false
 				response.Message = "User not local"
+				code = CodeSMTPUserNotLocal
 			case smtpErr.Code >= 400 && smtpErr.Code < 500:
 				response.CanReceive = false
 				response.Message = "Temporary failure"
+				code = CodeSMTPGreylisted
 			default:
 				response.CanReceive = false
 			}
@@ -193,13 +257,15 @@ func (v *SMTPValidator) tryRCPT(client *smtp.Client, email string) *SMTPResponse
 			response.Code = 0
 			response.Message = err.Error()
 			response.CanReceive = false
+			code = CodeSMTPConnectionFailed
 		}
 	} else {
 		response.Code = 250
 		response.Message = "Recipient accepted"
 		response.CanReceive = true
 		response.IsMailbox = true
+		code = CodeSMTPMailboxExists
 	}
 
-	return response
+	return response, code
 }
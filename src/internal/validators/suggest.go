@@ -0,0 +1,325 @@
+package validators
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"time"
+	"unicode/utf8"
+
+	"github.com/wizenheimer/bloombox/internal/loader"
+)
+
+// popularDomains lists the most used providers of data/free.txt, in order
+// of popularity, the ones found in the provider list are used to correct
+// domain typos
+var popularDomains = []string{
+	"gmail.com", "yahoo.com", "hotmail.com", "outlook.com", "aol.com",
+	"icloud.com", "live.com", "msn.com", "me.com", "mac.com", "mail.com",
+	"googlemail.com", "ymail.com", "rocketmail.com", "protonmail.com",
+	"proton.me", "zoho.com", "gmx.com", "gmx.de", "gmx.net", "web.de",
+	"t-online.de", "yandex.ru", "yandex.com", "mail.ru", "qq.com", "163.com",
+	"126.com", "naver.com", "rediffmail.com", "comcast.net", "verizon.net",
+	"att.net", "sbcglobal.net", "btinternet.com", "inbox.com",
+	"hotmail.co.uk", "yahoo.co.uk", "live.co.uk", "yahoo.co.in", "yahoo.fr",
+	"hotmail.fr", "orange.fr", "free.fr", "yahoo.de", "hotmail.de",
+	"outlook.de", "hotmail.it", "libero.it",
+}
+
+// tldTypos maps common top-level domain misspellings to their correction
+var tldTypos = map[string]string{
+	"co":   "com",
+	"cm":   "com",
+	"om":   "com",
+	"con":  "com",
+	"cmo":  "com",
+	"ocm":  "com",
+	"comm": "com",
+	"coom": "com",
+	"vom":  "com",
+	"xom":  "com",
+	"cim":  "com",
+	"nte":  "net",
+	"ne":   "net",
+	"nt":   "net",
+	"ogr":  "org",
+	"or":   "org",
+}
+
+// keyboardRows holds the QWERTY layout used to find adjacent keys
+var keyboardRows = []string{
+	"1234567890-",
+	"qwertyuiop",
+	"asdfghjkl",
+	"zxcvbnm",
+}
+
+// adjacentKeys maps each key to the keys next to it
+var adjacentKeys = buildAdjacentKeys()
+
+// buildAdjacentKeys computes key neighbours on the same and adjacent rows
+func buildAdjacentKeys() map[rune]map[rune]bool {
+	adjacent := make(map[rune]map[rune]bool)
+	for row, keys := range keyboardRows {
+		for col, key := range keys {
+			neighbours := make(map[rune]bool)
+			for r := row - 1; r <= row+1; r++ {
+				if r < 0 || r >= len(keyboardRows) {
+					continue
+				}
+				for c := col - 1; c <= col+1; c++ {
+					if c >= 0 && c < len(keyboardRows[r]) && !(r == row && c == col) {
+						neighbours[rune(keyboardRows[r][c])] = true
+					}
+				}
+			}
+			adjacent[key] = neighbours
+		}
+	}
+	return adjacent
+}
+
+// SuggestValidator suggests corrections for misspelled popular domains.
+// It is advisory and never fails validation.
+type SuggestValidator struct {
+	list     *listSource[*suggestDomains]
+	sources  []string // Files the domains are read from
+	disabled atomic.Bool
+}
+
+// suggestDomains holds the domains a misspelled domain may be corrected to,
+// in order of preference, and the domains known to be correct
+type suggestDomains struct {
+	candidates []string
+	known      map[string]bool
+}
+
+// NewSuggestValidator creates a new typo suggestion validator. Domains are
+// corrected to the popular providers listed in the providerFiles, such as
+// the free provider list, or to every popular provider without one, and to
+// the domains of filename, if given. Domains of the providerFiles are known
+// to be correct and never get a suggestion. Its source is the first
+// provider file, or filename without one, and a reload reads every file
+// again.
+func NewSuggestValidator(filename string, providerFiles ...string) (Validator, error) {
+	var sources []string
+	for _, providerFile := range providerFiles {
+		if providerFile != "" {
+			sources = append(sources, providerFile)
+		}
+	}
+	if filename != "" {
+		sources = append(sources, filename)
+	}
+	source := ""
+	if len(sources) > 0 {
+		source = sources[0]
+	}
+
+	list, err := newListSource(source, func() (*suggestDomains, int, error) {
+		return loadSuggestDomains(filename, providerFiles)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &SuggestValidator{
+		list:    list,
+		sources: sources,
+	}, nil
+}
+
+// loadSuggestDomains builds the suggestion domains from the popular
+// domains, filename and the providerFiles
+func loadSuggestDomains(filename string, providerFiles []string) (*suggestDomains, int, error) {
+	var providers map[string]bool
+	for _, providerFile := range providerFiles {
+		if providerFile == "" {
+			continue
+		}
+		entries, err := loader.LoadEntries(providerFile)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to load provider domains file: %w", err)
+		}
+		// Entries that aren't domains can't be typos of one, skip them
+		entries, _ = loader.NormalizeEntries(providerFile, entries, normalizeDomainEntry)
+		if providers == nil {
+			providers = make(map[string]bool, len(entries))
+		}
+		for _, entry := range entries {
+			providers[entry.Value] = true
+		}
+	}
+
+	domains := &suggestDomains{known: make(map[string]bool, len(providers))}
+	add := func(domain string) {
+		domain = strings.ToLower(strings.TrimSpace(domain))
+		if domain != "" && !slices.Contains(domains.candidates, domain) {
+			domains.known[domain] = true
+			domains.candidates = append(domains.candidates, domain)
+		}
+	}
+
+	for _, domain := range popularDomains {
+		if providers == nil || providers[domain] {
+			add(domain)
+		}
+	}
+
+	if filename != "" {
+		l := loader.NewFileLoader()
+		extra, err := l.LoadFromFile(filename)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to load suggestion domains file: %w", err)
+		}
+		for _, domain := range extra {
+			add(domain)
+		}
+	}
+
+	for domain := range providers {
+		domains.known[domain] = true
+	}
+
+	return domains, len(domains.candidates), nil
+}
+
+// Source returns the file the validator is reloaded from, empty when it
+// only knows the built-in popular domains
+func (v *SuggestValidator) Source() string { return v.list.Source() }
+
+// Sources returns the provider files and the suggestion file, a change to
+// any of them calls for a reload
+func (v *SuggestValidator) Sources() []string { return v.sources }
+
+func (v *SuggestValidator) Reload() (int, error) { return v.list.Reload() }
+
+func (v *SuggestValidator) Name() string { return "suggest" }
+
+func (v *SuggestValidator) IsEnabled() bool { return !v.disabled.Load() }
+
+func (v *SuggestValidator) SetEnabled(enabled bool) { v.disabled.Store(!enabled) }
+
+func (v *SuggestValidator) Validate(ctx context.Context, email string) *ValidationResult {
+	start := time.Now()
+
+	domain := extractDomain(email)
+	suggested, distance := v.suggest(domain)
+
+	result := &ValidationResult{
+		Valid:   true,
+		Message: "No suggestion",
+		Code:    CodeSuggestNone,
+		Details: map[string]interface{}{
+			"domain": domain,
+		},
+	}
+
+	if suggested != "" {
+		suggestion := email[:strings.LastIndex(email, "@")] + "@" + suggested
+		result.Message = fmt.Sprintf("Did you mean %s?", suggestion)
+		result.Code = CodeSuggestTypo
+		result.Details["suggestion"] = suggestion
+		result.Details["suggested_domain"] = suggested
+		result.Details["distance"] = distance
+	}
+
+	result.Duration = time.Since(start)
+	return result
+}
+
+// suggest returns the closest popular domain to a misspelled domain, or an
+// empty string when the domain is known or nothing is close enough
+func (v *SuggestValidator) suggest(domain string) (string, float64) {
+	domains := v.list.Load()
+	if domain == "" || domains.known[domain] {
+		return "", 0
+	}
+
+	candidates := map[string]float64{domain: 0}
+
+	// A misspelled top-level domain costs half an edit
+	if dot := strings.LastIndex(domain, "."); dot > 0 {
+		if fixed, ok := tldTypos[domain[dot+1:]]; ok {
+			candidates[domain[:dot+1]+fixed] = 0.5
+		}
+	}
+
+	name := domain
+	if dot := strings.Index(domain, "."); dot > 0 {
+		name = domain[:dot]
+	}
+	// Short names allow a single edit plus a TLD fix. Most names of three
+	// letters are a single edit away from a real domain, such as ge.com
+	// from me.com, so they only allow an adjacent key or a TLD fix.
+	maxDistance := 2.0
+	switch {
+	case len(name) <= 3:
+		maxDistance = 0.5
+	case len(name) <= 4:
+		maxDistance = 1.5
+	}
+
+	best, bestDistance := "", maxDistance+0.01
+	for _, known := range domains.candidates {
+		for candidate, cost := range candidates {
+			// Each rune of length difference is an edit
+			if math.Abs(float64(utf8.RuneCountInString(candidate)-utf8.RuneCountInString(known)))+cost >= bestDistance {
+				continue
+			}
+			distance := cost + editDistance(candidate, known)
+			if distance < bestDistance {
+				best, bestDistance = known, distance
+			}
+		}
+	}
+
+	if best == "" {
+		return "", 0
+	}
+	return best, bestDistance
+}
+
+// editDistance computes the Damerau-Levenshtein distance between a and b,
+// where substituting a key for an adjacent one costs half an edit
+func editDistance(a, b string) float64 {
+	ar, br := []rune(a), []rune(b)
+	n, m := len(ar), len(br)
+
+	d := make([][]float64, n+1)
+	for i := range d {
+		d[i] = make([]float64, m+1)
+		d[i][0] = float64(i)
+	}
+	for j := 0; j <= m; j++ {
+		d[0][j] = float64(j)
+	}
+
+	for i := 1; i <= n; i++ {
+		for j := 1; j <= m; j++ {
+			cost := 0.0
+			if ar[i-1] != br[j-1] {
+				cost = 1
+				if adjacentKeys[ar[i-1]][br[j-1]] {
+					cost = 0.5
+				}
+			}
+
+			d[i][j] = min(
+				d[i-1][j]+1,      // deletion
+				d[i][j-1]+1,      // insertion
+				d[i-1][j-1]+cost, // substitution
+			)
+
+			// transposition
+			if i > 1 && j > 1 && ar[i-1] == br[j-2] && ar[i-2] == br[j-1] {
+				d[i][j] = min(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+
+	return d[n][m]
+}
@@ -0,0 +1,147 @@
+package validators
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeList writes a list file of lines and returns its path
+func writeList(t *testing.T, dir, name string, lines ...string) string {
+	t.Helper()
+	filename := filepath.Join(dir, name)
+	if err := os.WriteFile(filename, []byte(strings.Join(lines, "\n")+"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return filename
+}
+
+func TestSuggest(t *testing.T) {
+	providers := writeList(t, t.TempDir(), "free.txt",
+		"gmail.com", "yahoo.com", "hotmail.com", "outlook.com", "aol.com", "me.com",
+		"gmx.de", "yahoo.co.jp", "yahoo.co.uk", "smallprovider.example",
+	)
+	v, err := NewSuggestValidator("", providers)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		domain string
+		want   string
+	}{
+		// Typos of popular providers
+		{"gmial.com", "gmail.com"},
+		{"gnail.com", "gmail.com"},
+		{"gmail.con", "gmail.com"},
+		{"hotmial.com", "hotmail.com"},
+		{"yaho.co", "yahoo.com"},
+		{"outlok.com", "outlook.com"},
+		{"aok.com", "aol.com"},
+		{"aol.co", "aol.com"},
+		{"gmx.dr", "gmx.de"},
+
+		// Known and unrelated domains
+		{"gmail.com", ""},
+		{"yahoo.co.jp", ""},
+		{"smallprovider.example", ""},
+		{"example.com", ""},
+		{"stripe.com", ""},
+
+		// Short names only allow an adjacent key or a TLD fix
+		{"ge.com", ""},
+		{"aon.com", ""},
+
+		// Popular providers missing from the provider list aren't suggested
+		{"iclod.com", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.domain, func(t *testing.T) {
+			result := v.Validate(context.Background(), "jane@"+tt.domain)
+			if !result.Valid {
+				t.Error("suggestion failed validation")
+			}
+			got, _ := result.Details["suggested_domain"].(string)
+			if got != tt.want {
+				t.Errorf("suggested domain = %q, want %q", got, tt.want)
+			}
+			if tt.want != "" && result.Details["suggestion"] != "jane@"+tt.want {
+				t.Errorf("suggestion = %v, want jane@%s", result.Details["suggestion"], tt.want)
+			}
+		})
+	}
+}
+
+func TestSuggestWithoutProviders(t *testing.T) {
+	extra := writeList(t, t.TempDir(), "extra.txt", "bigcorp.example")
+	v, err := NewSuggestValidator(extra)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for domain, want := range map[string]string{
+		"iclod.com":       "icloud.com",
+		"bigcrop.example": "bigcorp.example",
+	} {
+		got, _ := v.Validate(context.Background(), "jane@"+domain).Details["suggested_domain"].(string)
+		if got != want {
+			t.Errorf("suggested domain for %s = %q, want %q", domain, got, want)
+		}
+	}
+}
+
+func TestSuggestReload(t *testing.T) {
+	dir := t.TempDir()
+	providers := writeList(t, dir, "free.txt", "gmail.com")
+	v, err := NewSuggestValidator("", providers)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reloadable := v.(ReloadableValidator)
+	if reloadable.Source() != providers {
+		t.Errorf("Source() = %q, want the provider file", reloadable.Source())
+	}
+	if sources := v.(MultiSourceValidator).Sources(); len(sources) != 1 || sources[0] != providers {
+		t.Errorf("Sources() = %q, want the provider file", sources)
+	}
+
+	suggested := func(domain string) string {
+		got, _ := v.Validate(context.Background(), "jane@"+domain).Details["suggested_domain"].(string)
+		return got
+	}
+	if got := suggested("outlok.com"); got != "" {
+		t.Fatalf("suggested %q for a provider missing from the list", got)
+	}
+
+	writeList(t, dir, "free.txt", "gmail.com", "outlook.com", "gmial.com")
+	if _, err := reloadable.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	if got := suggested("outlok.com"); got != "outlook.com" {
+		t.Errorf("suggested %q after reloading, want outlook.com", got)
+	}
+	if got := suggested("gmial.com"); got != "" {
+		t.Errorf("suggested %q for a domain the reloaded list knows", got)
+	}
+}
+
+func TestSuggestSources(t *testing.T) {
+	dir := t.TempDir()
+	providers := writeList(t, dir, "free.txt", "gmail.com")
+	extra := writeList(t, dir, "suggest.txt", "acmemail.com")
+	v, err := NewSuggestValidator(extra, "", providers)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The suggestion file changes the domains as much as the providers do
+	if got := v.(ReloadableValidator).Source(); got != providers {
+		t.Errorf("Source() = %q, want the provider file", got)
+	}
+	if sources := v.(MultiSourceValidator).Sources(); len(sources) != 2 || sources[0] != providers || sources[1] != extra {
+		t.Errorf("Sources() = %q, want the provider and suggestion files", sources)
+	}
+}
@@ -2,25 +2,26 @@ package validators
 
 import (
 	"context"
-	"net/mail"
+	"sync/atomic"
 	"time"
 )
 
-// SyntaxValidator validates email syntax using Go's built-in RFC 5322 parser
+// SyntaxValidator validates email syntax following RFC 5321, with support
+// for internationalized addresses (RFC 6531) and IDN domains
 type SyntaxValidator struct {
-	enabled bool
+	disabled atomic.Bool
 }
 
 // NewSyntaxValidator creates a new syntax validator
 func NewSyntaxValidator() Validator {
-	return &SyntaxValidator{enabled: true}
+	return &SyntaxValidator{}
 }
 
 func (v *SyntaxValidator) Name() string { return "syntax" }
 
-func (v *SyntaxValidator) IsEnabled() bool { return v.enabled }
+func (v *SyntaxValidator) IsEnabled() bool { return !v.disabled.Load() }
 
-func (v *SyntaxValidator) SetEnabled(enabled bool) { v.enabled = enabled }
+func (v *SyntaxValidator) SetEnabled(enabled bool) { v.disabled.Store(!enabled) }
 
 func (v *SyntaxValidator) Validate(ctx context.Context, email string) *ValidationResult {
 	start := time.Now()
@@ -29,16 +30,24 @@ func (v *SyntaxValidator) Validate(ctx context.Context, email string) *Validatio
 		Details: make(map[string]interface{}),
 	}
 
-	addr, err := mail.ParseAddress(email)
+	addr, err := ParseAddress(email)
 	if err != nil {
 		result.Valid = false
 		result.Message = "Invalid email syntax"
+		result.Code = SyntaxCode(err)
 		result.Error = err.Error()
 	} else {
 		result.Valid = true
 		result.Message = "Valid email syntax"
-		result.Details["parsed_address"] = addr.Address
-		result.Details["display_name"] = addr.Name
+		result.Code = CodeSyntaxValid
+		result.Details["parsed_address"] = addr.String()
+		// Only bare addresses are accepted, they carry no display name
+		result.Details["display_name"] = ""
+		result.Details["local_part"] = addr.LocalPart
+		result.Details["domain"] = addr.Domain
+		result.Details["domain_ascii"] = addr.ASCIIDomain
+		result.Details["is_eai"] = addr.IsEAI
+		result.Details["is_idn"] = addr.IsIDN
 	}
 
 	result.Duration = time.Since(start)
@@ -0,0 +1,47 @@
+package validators
+
+import (
+	"context"
+	"testing"
+)
+
+func TestSyntaxValidatorDetails(t *testing.T) {
+	tests := []struct {
+		email string
+		want  map[string]interface{}
+	}{
+		{"user@example.com", map[string]interface{}{
+			"parsed_address": "user@example.com",
+			"display_name":   "",
+			"domain_ascii":   "example.com",
+			"is_eai":         false,
+			"is_idn":         false,
+		}},
+		// The parsed address has its domain normalized, the A-label form
+		// is given apart
+		{"müller@BÜCHER.de", map[string]interface{}{
+			"parsed_address": "müller@bücher.de",
+			"display_name":   "",
+			"local_part":     "müller",
+			"domain":         "bücher.de",
+			"domain_ascii":   "xn--bcher-kva.de",
+			"is_eai":         true,
+			"is_idn":         true,
+		}},
+	}
+
+	v := NewSyntaxValidator()
+	for _, tt := range tests {
+		t.Run(tt.email, func(t *testing.T) {
+			result := v.Validate(context.Background(), tt.email)
+			if !result.Valid {
+				t.Fatalf("%s rejected: %s", tt.email, result.Error)
+			}
+			for key, want := range tt.want {
+				if got, ok := result.Details[key]; !ok || got != want {
+					t.Errorf("Details[%q] = %v, want %v", key, got, want)
+				}
+			}
+		})
+	}
+}
@@ -4,18 +4,23 @@ import (
 	"context"
 	"net"
 	"time"
+
+	"github.com/wizenheimer/bloombox/internal/filter"
+	"github.com/wizenheimer/bloombox/internal/loader"
 )
 
 // ValidationResult represents the result of a single validator
 type ValidationResult struct {
 	Valid    bool                   `json:"valid"`
 	Message  string                 `json:"message"`
+	Code     string                 `json:"code,omitempty"`
 	Details  map[string]interface{} `json:"details,omitempty"`
 	Duration time.Duration          `json:"duration"`
 	Error    string                 `json:"error,omitempty"`
 }
 
-// Validator interface for all validation types
+// Validator interface for all validation types. IsEnabled and SetEnabled
+// may be called concurrently with Validate.
 type Validator interface {
 	Name() string
 	Validate(ctx context.Context, email string) *ValidationResult
@@ -23,6 +28,29 @@ type Validator interface {
 	SetEnabled(enabled bool)
 }
 
+// ListValidator is implemented by validators backed by a list filter
+type ListValidator interface {
+	FilterStats() filter.Stats
+	LoadReport() loader.LoadReport
+}
+
+// ReloadableValidator is implemented by validators built from a list file.
+// Reload rebuilds the list and swaps it in without blocking Validate, and
+// returns the number of items loaded. A file that fails to load leaves the
+// current list in place.
+type ReloadableValidator interface {
+	Source() string
+	Reload() (int, error)
+}
+
+// MultiSourceValidator is implemented by reloadable validators built from
+// more than one list file. Sources returns every file, Source the main one,
+// and a change to any of them calls for a Reload.
+type MultiSourceValidator interface {
+	ReloadableValidator
+	Sources() []string
+}
+
 // MXRecord represents an MX record
 type MXRecord struct {
 	Host     string `json:"host"`
@@ -37,6 +65,7 @@ type SMTPResponse struct {
 	CanReceive bool   `json:"can_receive"`
 	IsMailbox  bool   `json:"is_mailbox"`
 	IsCatchAll bool   `json:"is_catch_all"`
+	SMTPUTF8   bool   `json:"smtputf8"` // Server advertises SMTPUTF8 (RFC 6531)
 }
 
 // DialFunc represents a custom dial function for proxy support
@@ -1,15 +1,27 @@
 // internal/validators/utils.go
 package validators
 
-import "strings"
+import (
+	"fmt"
+	"strings"
 
-// extractDomain extracts domain from email address
+	"github.com/wizenheimer/bloombox/internal/filter"
+	"github.com/wizenheimer/bloombox/internal/loader"
+	"github.com/wizenheimer/bloombox/internal/normalizer"
+)
+
+// extractDomain extracts domain from email address in its IDNA A-label
+// form, as used by list files, DNS and SMTP
 func extractDomain(email string) string {
 	parts := strings.Split(email, "@")
 	if len(parts) != 2 {
 		return ""
 	}
-	return strings.ToLower(parts[1])
+	domain := strings.ToLower(parts[1])
+	if ascii, err := normalizer.ToASCII(domain); err == nil {
+		return ascii
+	}
+	return domain
 }
 
 // extractLocalPart extracts local part from email address
@@ -20,3 +32,113 @@ func extractLocalPart(email string) string {
 	}
 	return strings.ToLower(parts[0])
 }
+
+// listFilterType resolves the filter type of a list validator. An empty
+// filter type keeps exact matching for lists with fewer than exactBelow
+// items or when the false positive rate is 0, and a hybrid filter otherwise
+func listFilterType(filterType filter.Type, count int, falsePositiveRate float64, exactBelow int) filter.Type {
+	if filterType != "" {
+		return filterType
+	}
+	if falsePositiveRate == 0 || count < exactBelow {
+		return filter.TypeMap
+	}
+	return filter.TypeHybrid
+}
+
+// newListFilter creates the filter backing a list validator
+func newListFilter(filterType filter.Type, count int, falsePositiveRate float64, exactBelow int) (filter.Filter, error) {
+	return filter.New(listFilterType(filterType, count, falsePositiveRate, exactBelow), count, falsePositiveRate)
+}
+
+// loadList builds the filter for a list file from its entries as cleaned
+// up by normalize, refusing the file when more than maxErrorRate of its
+// entries are rejected. With a snapshot file the filter is restored from
+// the snapshot when it was built from the same list with the same settings,
+// and the snapshot is rewritten otherwise. The metadata of CSV and JSONL
+// list files is kept beside the filter.
+func loadList(filename, snapshotFile string, filterType filter.Type, falsePositiveRate float64,
+	exactBelow int, maxErrorRate float64, normalize func(string) (string, error)) (*listData, error) {
+	var checksum [32]byte
+	var f filter.Filter
+	var snapshotReport loader.LoadReport
+	if snapshotFile != "" {
+		sum, err := loader.Checksum(filename)
+		if err != nil {
+			return nil, err
+		}
+		checksum = sum
+
+		header, snapshot, err := filter.ReadSnapshot(snapshotFile)
+		if err == nil && header.Checksum == checksum && header.FalsePositiveRate == falsePositiveRate &&
+			header.Type == listFilterType(filterType, int(header.Count), falsePositiveRate, exactBelow) {
+			f = snapshot
+			snapshotReport = loader.LoadReport{
+				File:       filename,
+				Entries:    int(header.Entries),
+				Loaded:     int(header.Count),
+				Duplicates: int(header.Entries - header.Rejected - header.Count),
+				Rejected:   int(header.Rejected),
+				Snapshot:   true,
+			}
+		}
+	}
+
+	// A snapshot of a list with more rejected entries than now allowed is
+	// ignored, the list is read again to report them
+	if f != nil && snapshotReport.ErrorRate() > maxErrorRate {
+		f = nil
+	}
+
+	// Snapshots don't hold metadata, so rich list files are read either way
+	if f != nil && !loader.IsRichList(filename) {
+		return &listData{filter: f, report: snapshotReport}, nil
+	}
+	raw, err := loader.LoadEntries(filename)
+	if err != nil {
+		return nil, err
+	}
+	entries, report := loader.NormalizeEntries(filename, raw, normalize)
+	if err := report.CheckErrorRate(maxErrorRate); err != nil {
+		return nil, err
+	}
+
+	list := &listData{filter: f, report: report}
+	if loader.IsRichList(filename) {
+		list.meta = make(map[string]loader.ListEntry, len(entries))
+		for _, entry := range entries {
+			list.meta[entry.Value] = entry
+		}
+	}
+	if f != nil {
+		return list, nil
+	}
+
+	// Expired entries stay in the filter, so it only changes with the file
+	// and lookups check the expiry
+	f, err = newListFilter(filterType, len(entries), falsePositiveRate, exactBelow)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create filter: %w", err)
+	}
+	for _, entry := range entries {
+		if err := f.Add(entry.Value); err != nil {
+			return nil, fmt.Errorf("failed to add %q: %w", entry.Value, err)
+		}
+	}
+	list.filter = f
+
+	if snapshotFile != "" {
+		header := filter.SnapshotHeader{
+			FalsePositiveRate: falsePositiveRate,
+			Count:             uint64(len(entries)),
+			Entries:           uint64(report.Entries),
+			Rejected:          uint64(report.Rejected),
+			Checksum:          checksum,
+		}
+		if err := filter.WriteSnapshot(snapshotFile, header, f); err != nil {
+			return nil, err
+		}
+	}
+
+	return list, nil
+}
@@ -0,0 +1,74 @@
+package emailchecker
+
+import (
+	"fmt"
+	"time"
+
+	lru "github.com/hashicorp/golang-lru/v2"
+)
+
+// Cache stores check results between checks. Implementations are
+// responsible for expiring entries and must be safe for concurrent use.
+// Caches that hold resources can implement io.Closer, they are closed
+// by EmailChecker.Close.
+type Cache interface {
+	Get(key string) (*CheckResult, bool)
+	Add(key string, result *CheckResult)
+	Remove(key string)
+	Purge()
+	Len() int
+}
+
+// LRUCache is an in-memory Cache with LRU eviction, the default backend
+type LRUCache struct {
+	cache *lru.Cache[string, *CheckResult]
+	ttl   time.Duration
+}
+
+// NewLRUCache creates an in-memory cache holding up to size results for ttl
+func NewLRUCache(size int, ttl time.Duration) (*LRUCache, error) {
+	cache, err := lru.New[string, *CheckResult](size)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create lru cache: %w", err)
+	}
+
+	return &LRUCache{
+		cache: cache,
+		ttl:   ttl,
+	}, nil
+}
+
+// Get returns the cached result for key if it has not expired
+func (c *LRUCache) Get(key string) (*CheckResult, bool) {
+	result, ok := c.cache.Get(key)
+	if !ok {
+		return nil, false
+	}
+
+	if time.Since(result.Timestamp) >= c.ttl {
+		c.cache.Remove(key)
+		return nil, false
+	}
+
+	return result, true
+}
+
+// Add stores a result under key
+func (c *LRUCache) Add(key string, result *CheckResult) {
+	c.cache.Add(key, result)
+}
+
+// Remove deletes the result stored under key
+func (c *LRUCache) Remove(key string) {
+	c.cache.Remove(key)
+}
+
+// Purge deletes all cached results
+func (c *LRUCache) Purge() {
+	c.cache.Purge()
+}
+
+// Len returns the number of cached results
+func (c *LRUCache) Len() int {
+	return c.cache.Len()
+}
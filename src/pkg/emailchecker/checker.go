@@ -1,23 +1,51 @@
+// Package emailchecker validates email addresses with a configurable set of
+// validators.
+//
+// The built-in validators are exposed through constructors such as
+// NewMXValidator and NewSMTPValidator, and the instances configured on a
+// checker can be retrieved with EmailChecker.Validator. Applications can
+// wrap them, or plug in their own validators, with RegisterValidator.
 package emailchecker
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"net/mail"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
 	"strings"
 	"sync"
 	"time"
 
-	lru "github.com/hashicorp/golang-lru/v2"
+	"github.com/wizenheimer/bloombox/internal/loader"
+	"github.com/wizenheimer/bloombox/internal/validators"
+	"github.com/wizenheimer/bloombox/pkg/logger"
+	"go.uber.org/zap"
+	"golang.org/x/sync/singleflight"
 )
 
 // EmailChecker is the main email checking service
 type EmailChecker struct {
-	config     *Config
-	validators map[string]Validator
-	cache      *lru.Cache[string, *CheckResult]
-	semaphore  chan struct{}
-	mu         sync.RWMutex
+	config      *Config
+	validators  map[string]Validator
+	policies    map[string]*Policy
+	cache       Cache
+	domainCache *validators.DomainCache // Shared DNS-derived facts, nil if disabled
+	lists       *loader.HTTPLoader      // Fetches list files given as URLs
+	limiter     *limiter
+	flight      singleflight.Group     // Coalesces identical in-flight checks
+	flights     map[string]*flightCall // Callers waiting on each coalesced check
+	flightMu    sync.Mutex
+	stopWatch   context.CancelFunc            // Stops the list file watchers and refreshes
+	watchCtx    context.Context               // Parent of the list file watchers, nil if unwatched
+	watches     map[string]context.CancelFunc // Stops the watcher of each watched validator
+	fingerprint string                        // Prefixes cache keys, see updateFingerprint
+	listSums    map[string]string             // Checksums of the list files by validator
+	mu          sync.RWMutex
 }
 
 // New creates a new EmailChecker instance
@@ -25,9 +53,15 @@ func New(config *Config) (*EmailChecker, error) {
 	if config == nil {
 		config = DefaultConfig()
 	}
+	if config.WatchLists && config.ListPollInterval <= 0 {
+		return nil, fmt.Errorf("list poll interval must be positive, got %v", config.ListPollInterval)
+	}
+	if config.ListRefreshInterval < 0 {
+		return nil, fmt.Errorf("list refresh interval must not be negative, got %v", config.ListRefreshInterval)
+	}
 
 	// Initialize cache
-	cache, err := lru.New[string, *CheckResult](config.CacheSize)
+	cache, err := newCache(config)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create cache: %w", err)
 	}
@@ -35,8 +69,21 @@ func New(config *Config) (*EmailChecker, error) {
 	checker := &EmailChecker{
 		config:     config,
 		validators: make(map[string]Validator),
+		listSums:   make(map[string]string),
+		watches:    make(map[string]context.CancelFunc),
+		flights:    make(map[string]*flightCall),
 		cache:      cache,
-		semaphore:  make(chan struct{}, config.MaxConcurrentValidations),
+		limiter: newLimiter(
+			config.MaxConcurrentValidations,
+			config.MaxQueueLength,
+			config.MaxQueueWait,
+		),
+	}
+
+	checker.lists = newListLoader(config)
+
+	if config.DomainCacheTTL > 0 {
+		checker.domainCache = validators.NewDomainCache(config.DomainCacheSize, config.DomainCacheTTL)
 	}
 
 	// Initialize validators
@@ -44,9 +91,83 @@ func New(config *Config) (*EmailChecker, error) {
 		return nil, fmt.Errorf("failed to initialize validators: %w", err)
 	}
 
+	// Policies name validators, so they are checked once those are built
+	if err := checker.initializePolicies(); err != nil {
+		return nil, fmt.Errorf("failed to initialize policies: %w", err)
+	}
+
+	for name := range checker.validators {
+		checker.updateListSum(name)
+	}
+	checker.updateFingerprint()
+
+	checker.logLoadReports()
+
+	if config.WatchLists || config.ListRefreshInterval > 0 {
+		ctx, cancel := context.WithCancel(context.Background())
+		checker.stopWatch = cancel
+		if config.WatchLists {
+			checker.watchLists(ctx)
+		}
+		if config.ListRefreshInterval > 0 {
+			checker.refreshLists(ctx)
+		}
+	}
+
 	return checker, nil
 }
 
+// newCache returns the configured cache backend, a disk cache when a
+// cache file is set and an in-memory LRU cache otherwise
+func newCache(config *Config) (Cache, error) {
+	if config.Cache != nil {
+		return config.Cache, nil
+	}
+	if config.CacheFile != "" {
+		return NewDiskCache(config.CacheFile, config.CacheTimeout, config.CacheSize)
+	}
+	return NewLRUCache(config.CacheSize, config.CacheTimeout)
+}
+
+// newListLoader returns the loader for list files given as URLs, dialing
+// through the configured dial function
+func newListLoader(config *Config) *loader.HTTPLoader {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if config.DialFunc != nil {
+		transport.DialContext = config.DialFunc
+	}
+
+	cacheDir := config.ListCacheDir
+	if cacheDir == "" {
+		cacheDir = defaultListCacheDir()
+	}
+	return loader.NewHTTPLoader(&http.Client{Transport: transport}, cacheDir, config.MaxListSize)
+}
+
+// defaultListCacheDir returns a list cache directory private to the user,
+// in the user cache directory, or a fresh temporary directory when there is
+// none. A shared, predictable path would let other users plant lists.
+func defaultListCacheDir() string {
+	if dir, err := os.UserCacheDir(); err == nil {
+		return filepath.Join(dir, "bloombox", "lists")
+	}
+	// Without a temporary directory either, downloads fail to be cached
+	dir, _ := os.MkdirTemp("", "bloombox-lists-")
+	return dir
+}
+
+// Close releases resources held by the checker, such as list file
+// watchers, list refreshes and a disk cache
+func (e *EmailChecker) Close() error {
+	if e.stopWatch != nil {
+		e.stopWatch()
+	}
+	if closer, ok := e.cache.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
 // initializeValidators sets up all validators
 func (e *EmailChecker) initializeValidators() error {
 	// Always create syntax validator
@@ -54,21 +175,26 @@ func (e *EmailChecker) initializeValidators() error {
 
 	// File-based validators - only create if files are provided
 	if e.config.DisposableEmailsFile != "" {
-		validator, err := NewDisposableValidator(
-			e.config.DisposableEmailsFile,
-			e.config.FalsePositiveRate,
-		)
+		filename, err := e.fetchList(e.config.DisposableEmailsFile)
+		if err != nil {
+			return fmt.Errorf("failed to create disposable validator: %w", err)
+		}
+		validator, err := NewDisposableValidatorWithOptions(filename, e.config.FalsePositiveRate, e.listOptions("disposable"))
 		if err != nil {
 			return fmt.Errorf("failed to create disposable validator: %w", err)
 		}
 		e.validators["disposable"] = validator
 	}
 
+	// The free providers are known domains to the suggest validator
+	var freeFile string
 	if e.config.FreeEmailsFile != "" {
-		validator, err := NewFreeValidator(
-			e.config.FreeEmailsFile,
-			e.config.FalsePositiveRate,
-		)
+		filename, err := e.fetchList(e.config.FreeEmailsFile)
+		if err != nil {
+			return fmt.Errorf("failed to create free validator: %w", err)
+		}
+		freeFile = filename
+		validator, err := NewFreeValidatorWithOptions(filename, e.config.FalsePositiveRate, e.listOptions("free"))
 		if err != nil {
 			return fmt.Errorf("failed to create free validator: %w", err)
 		}
@@ -76,7 +202,11 @@ func (e *EmailChecker) initializeValidators() error {
 	}
 
 	if e.config.RoleEmailsFile != "" {
-		validator, err := NewRoleValidator(e.config.RoleEmailsFile)
+		filename, err := e.fetchList(e.config.RoleEmailsFile)
+		if err != nil {
+			return fmt.Errorf("failed to create role validator: %w", err)
+		}
+		validator, err := NewRoleValidator(filename)
 		if err != nil {
 			return fmt.Errorf("failed to create role validator: %w", err)
 		}
@@ -84,7 +214,11 @@ func (e *EmailChecker) initializeValidators() error {
 	}
 
 	if e.config.BanWordsFile != "" {
-		validator, err := NewBanWordsValidator(e.config.BanWordsFile)
+		filename, err := e.fetchList(e.config.BanWordsFile)
+		if err != nil {
+			return fmt.Errorf("failed to create ban words validator: %w", err)
+		}
+		validator, err := NewBanWordsValidator(filename)
 		if err != nil {
 			return fmt.Errorf("failed to create ban words validator: %w", err)
 		}
@@ -92,10 +226,11 @@ func (e *EmailChecker) initializeValidators() error {
 	}
 
 	if e.config.BlackListEmailsFile != "" {
-		validator, err := NewBlackListEmailsValidator(
-			e.config.BlackListEmailsFile,
-			e.config.FalsePositiveRate,
-		)
+		filename, err := e.fetchList(e.config.BlackListEmailsFile)
+		if err != nil {
+			return fmt.Errorf("failed to create blacklist emails validator: %w", err)
+		}
+		validator, err := NewBlackListEmailsValidatorWithOptions(filename, e.config.FalsePositiveRate, e.listOptions("blacklist_emails"))
 		if err != nil {
 			return fmt.Errorf("failed to create blacklist emails validator: %w", err)
 		}
@@ -103,16 +238,39 @@ func (e *EmailChecker) initializeValidators() error {
 	}
 
 	if e.config.BlackListDomainsFile != "" {
-		validator, err := NewBlackListDomainsValidator(
-			e.config.BlackListDomainsFile,
-			e.config.FalsePositiveRate,
-		)
+		filename, err := e.fetchList(e.config.BlackListDomainsFile)
+		if err != nil {
+			return fmt.Errorf("failed to create blacklist domains validator: %w", err)
+		}
+		validator, err := NewBlackListDomainsValidatorWithOptions(filename, e.config.FalsePositiveRate, e.listOptions("blacklist_domains"))
 		if err != nil {
 			return fmt.Errorf("failed to create blacklist domains validator: %w", err)
 		}
 		e.validators["blacklist_domains"] = validator
 	}
 
+	if e.config.BlackListPatternsFile != "" {
+		filename, err := e.fetchList(e.config.BlackListPatternsFile)
+		if err != nil {
+			return fmt.Errorf("failed to create blacklist patterns validator: %w", err)
+		}
+		validator, err := NewBlackListPatternsValidator(filename)
+		if err != nil {
+			return fmt.Errorf("failed to create blacklist patterns validator: %w", err)
+		}
+		e.validators["blacklist_patterns"] = validator
+	}
+
+	suggestFile, err := e.fetchList(e.config.SuggestDomainsFile)
+	if err != nil {
+		return fmt.Errorf("failed to create suggest validator: %w", err)
+	}
+	suggestValidator, err := NewSuggestValidator(suggestFile, freeFile)
+	if err != nil {
+		return fmt.Errorf("failed to create suggest validator: %w", err)
+	}
+	e.validators["suggest"] = suggestValidator
+
 	// Network validators - always available
 	e.validators["mx"] = NewMXValidator(e.config.ValidationTimeout, e.config.DialFunc)
 
@@ -134,6 +292,56 @@ func (e *EmailChecker) initializeValidators() error {
 	return nil
 }
 
+// fetchList returns a local path for a list source, downloading lists
+// given as URLs into the list cache
+func (e *EmailChecker) fetchList(source string) (string, error) {
+	return e.fetchListContext(context.Background(), source)
+}
+
+// fetchListContext is like fetchList, giving up on the download when ctx
+// is done
+func (e *EmailChecker) fetchListContext(ctx context.Context, source string) (string, error) {
+	fetchCtx := ctx
+	if e.config.ListFetchTimeout > 0 {
+		var cancel context.CancelFunc
+		fetchCtx, cancel = context.WithTimeout(ctx, e.config.ListFetchTimeout)
+		defer cancel()
+	}
+
+	path, err := e.lists.Fetch(fetchCtx, source)
+	var stale *loader.StaleError
+	if errors.As(err, &stale) {
+		// Not worth a warning when the caller gave up on the download
+		if ctx.Err() == nil {
+			logger.Warn("Failed to download list, using the cached copy",
+				zap.String("url", stale.URL),
+				zap.Error(stale.Err),
+			)
+		}
+		return path, nil
+	}
+	return path, err
+}
+
+// listOptions returns the options of the named list validator
+func (e *EmailChecker) listOptions(name string) ListOptions {
+	return ListOptions{
+		FilterType:   e.config.FilterTypes[name],
+		SnapshotFile: e.snapshotFile(name),
+		Strict:       e.config.StrictLists,
+		MaxErrorRate: e.config.MaxListErrorRate,
+	}
+}
+
+// snapshotFile returns the filter snapshot path for a list validator, or ""
+// when snapshots are disabled
+func (e *EmailChecker) snapshotFile(name string) string {
+	if e.config.SnapshotDir == "" {
+		return ""
+	}
+	return filepath.Join(e.config.SnapshotDir, name+".snapshot")
+}
+
 // setEnabledValidators enables/disables validators based on config
 func (e *EmailChecker) setEnabledValidators() {
 	enabledMap := make(map[string]bool)
@@ -148,149 +356,322 @@ func (e *EmailChecker) setEnabledValidators() {
 
 // Check performs email validation
 func (e *EmailChecker) Check(email string) *CheckResult {
-	return e.CheckWithValidators(email, nil)
+	return e.CheckContext(context.Background(), email, CheckOptions{})
 }
 
 // CheckWithValidators performs email validation with specific validators
 func (e *EmailChecker) CheckWithValidators(email string, validatorNames []string) *CheckResult {
+	return e.CheckContext(context.Background(), email, CheckOptions{Validators: validatorNames})
+}
+
+// CheckContext performs email validation bounded by the given context.
+// Cancelling ctx aborts in-flight validators, unless other callers are
+// waiting on the same check, and opts.Timeout, when set, further limits how
+// long the whole check may take.
+func (e *EmailChecker) CheckContext(ctx context.Context, email string, opts CheckOptions) *CheckResult {
 	email = strings.ToLower(strings.TrimSpace(email))
+	canonical := CanonicalEmail(email)
 	start := time.Now()
+	opts, policy, err := e.applyPolicy(opts)
+	if err != nil {
+		// Checking without the policy would quietly run other validators
+		// than the caller asked for
+		return &CheckResult{
+			Email:          email,
+			Policy:         opts.Policy,
+			CanonicalEmail: canonical,
+			Timestamp:      time.Now(),
+			Results:        make(map[string]*ValidationResult),
+			Verdict:        VerdictUnknown,
+			Summary:        &CheckSummary{},
+			Error:          err.Error(),
+		}
+	}
+	validatorsToRun := e.getValidatorsToRun(opts, policy)
+
+	// Check cache first, addresses reaching the same mailbox share entries
+	// unless the validators to run read the parts that set them apart
+	thresholds := e.scoreThresholds()
+	if opts.Thresholds != nil {
+		thresholds = *opts.Thresholds
+	}
 
-	// Check cache first
-	cacheKey := e.buildCacheKey(email, validatorNames)
+	cacheKey := e.buildCacheKey(cacheAddress(email, canonical, validatorsToRun), opts, thresholds)
 	if cached, ok := e.cache.Get(cacheKey); ok {
-		if time.Since(cached.Timestamp) < e.config.CacheTimeout {
-			return cached
+		return withEmail(cached, email)
+	}
+
+	// Coalesce identical concurrent checks into a single execution, which
+	// runs until its last waiting caller gives up
+	call := e.joinFlight(ctx, cacheKey)
+	defer e.leaveFlight(cacheKey, call)
+	ch := e.flight.DoChan(cacheKey, func() (interface{}, error) {
+		return e.check(call.ctx, email, opts, thresholds, validatorsToRun, cacheKey), nil
+	})
+
+	select {
+	case res := <-ch:
+		result := res.Val.(*CheckResult)
+		if res.Shared && result.Partial && ctx.Err() == nil {
+			// The check we joined was cut short as all its callers had
+			// given up before we joined, run ours
+			return e.check(ctx, email, opts, thresholds, validatorsToRun, cacheKey)
+		}
+		return withEmail(result, email)
+
+	case <-ctx.Done():
+		// Stop waiting, the shared check keeps running for the others
+		result := &CheckResult{
+			Email:          email,
+			Policy:         opts.Policy,
+			CanonicalEmail: canonical,
+			Timestamp:      time.Now(),
+			Duration:       time.Since(start),
+			Results:        make(map[string]*ValidationResult),
+			Partial:        true,
 		}
-		e.cache.Remove(cacheKey)
+		e.calculateSummary(result)
+		e.calculateScore(result, thresholds)
+		return result
+	}
+}
+
+// flightCall is the context a coalesced check runs under, cancelled once
+// every caller waiting on the check has given up
+type flightCall struct {
+	ctx     context.Context
+	cancel  context.CancelFunc
+	waiters int
+}
+
+// joinFlight registers a caller waiting on the check under key. The check
+// keeps the values of the first caller's ctx but not its cancellation, so
+// it runs on for the callers that joined it.
+func (e *EmailChecker) joinFlight(ctx context.Context, key string) *flightCall {
+	e.flightMu.Lock()
+	defer e.flightMu.Unlock()
+
+	call, ok := e.flights[key]
+	if !ok {
+		callCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+		call = &flightCall{ctx: callCtx, cancel: cancel}
+		e.flights[key] = call
+	}
+	call.waiters++
+	return call
+}
+
+// leaveFlight unregisters a caller, cancelling the check when no caller is
+// left waiting on it
+func (e *EmailChecker) leaveFlight(key string, call *flightCall) {
+	e.flightMu.Lock()
+	defer e.flightMu.Unlock()
+
+	call.waiters--
+	if call.waiters == 0 {
+		call.cancel()
+		delete(e.flights, key)
+	}
+}
+
+// domainValidators read nothing but the domain of an address as written
+var domainValidators = map[string]bool{
+	"disposable":        true,
+	"free":              true,
+	"blacklist_domains": true,
+	"mx":                true,
+}
+
+// cacheAddress returns the address a check is cached and coalesced under:
+// the canonical form of email, along with the parts of email as written
+// that differ from it and that the validators to run read. Every validator
+// reads the domain, and validators such as role and smtp, as well as
+// registered ones, read the local part as written.
+func cacheAddress(email, canonical string, validatorNames []string) string {
+	if email == canonical {
+		return email
+	}
+	// Invalid addresses must not share the results of a valid canonical form
+	if _, err := validators.ParseAddress(email); err != nil {
+		return email
+	}
+
+	at, canonicalAt := strings.LastIndex(email, "@"), strings.LastIndex(canonical, "@")
+	address := canonical
+	if domain := email[at+1:]; domain != canonical[canonicalAt+1:] {
+		address += "|domain=" + domain
+	}
+	if local := email[:at]; local != canonical[:canonicalAt] && slices.ContainsFunc(validatorNames, func(name string) bool {
+		return !domainValidators[name]
+	}) {
+		address += "|local=" + local
+	}
+	return address
+}
+
+// withEmail returns result as seen by a caller asking for email. Results
+// are shared between addresses with the same cache address, so the caller
+// gets a copy carrying its own address when they differ.
+func withEmail(result *CheckResult, email string) *CheckResult {
+	if result.Email == email {
+		return result
+	}
+	copied := *result
+	copied.Email = email
+	return &copied
+}
+
+// check runs a single check that missed the cache and caches its result
+func (e *EmailChecker) check(ctx context.Context, email string, opts CheckOptions, thresholds ScoreThresholds, validatorsToRun []string, cacheKey string) *CheckResult {
+	start := time.Now()
+
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
 	}
 
 	// Create result
 	result := &CheckResult{
-		Email:     email,
-		Timestamp: time.Now(),
-		Results:   make(map[string]*ValidationResult),
+		Email:          email,
+		Policy:         opts.Policy,
+		CanonicalEmail: CanonicalEmail(email),
+		Timestamp:      time.Now(),
+		Results:        make(map[string]*ValidationResult),
 	}
 
 	// Validate basic email format first
-	if _, err := mail.ParseAddress(email); err != nil {
+	addr, err := validators.ParseAddress(email)
+	if err != nil {
 		result.Duration = time.Since(start)
 		result.Results["syntax"] = &ValidationResult{
 			Valid:   false,
 			Message: "Invalid email syntax",
+			Code:    Code(validators.SyntaxCode(err)),
 			Error:   err.Error(),
 		}
+		e.calculateSummary(result)
+		e.calculateScore(result, thresholds)
 		return result
 	}
-
-	// Determine which validators to run
-	validatorsToRun := e.getValidatorsToRun(validatorNames)
+	result.Domain = addr.Domain
+	result.DomainASCII = addr.ASCIIDomain
 
 	// Run validators
-	e.runValidators(result, validatorsToRun)
+	e.runValidators(ctx, result, validatorsToRun, opts.Priority)
+	if ctx.Err() != nil {
+		result.Partial = true
+	}
 
-	// Calculate overall validity and summary
+	// Calculate overall validity, summary and score
 	e.calculateSummary(result)
+	e.calculateScore(result, thresholds)
 
 	result.Duration = time.Since(start)
 
-	// Cache result
-	e.cache.Add(cacheKey, result)
+	// Results of a cancelled, expired or partial check are incomplete,
+	// don't cache them
+	if ctx.Err() == nil && !result.Partial {
+		e.cache.Add(cacheKey, result)
+	}
 
 	return result
 }
 
-// runValidators executes the specified validators
-func (e *EmailChecker) runValidators(result *CheckResult, validatorNames []string) {
-	var wg sync.WaitGroup
-	resultsChan := make(chan struct {
-		name string
-		res  *ValidationResult
-	}, len(validatorNames))
+// getValidatorsToRun determines which validators to execute. The enabled
+// set is layered: global defaults, then the policy, then the request's own
+// overrides, none of which affect other checks.
+func (e *EmailChecker) getValidatorsToRun(opts CheckOptions, policy *Policy) []string {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
 
-	for _, name := range validatorNames {
-		validator, exists := e.validators[name]
-		if !exists || !validator.IsEnabled() {
-			continue
-		}
+	enabled := make(map[string]bool, len(e.validators))
+	for name, validator := range e.validators {
+		enabled[name] = validator.IsEnabled()
+	}
 
-		wg.Add(1)
-		go func(vName string, v Validator) {
-			defer wg.Done()
-
-			// Acquire semaphore
-			select {
-			case e.semaphore <- struct{}{}:
-				defer func() { <-e.semaphore }()
-			default:
-				// Skip if too many concurrent validations
-				resultsChan <- struct {
-					name string
-					res  *ValidationResult
-				}{vName, &ValidationResult{
-					Valid:   false,
-					Message: "skipped due to rate limiting",
-				}}
-				return
+	if policy != nil {
+		if len(policy.Validators) > 0 {
+			// Policy validators run even when disabled globally
+			clear(enabled)
+			for _, name := range policy.Validators {
+				enabled[name] = true
 			}
-
-			ctx, cancel := context.WithTimeout(context.Background(), e.config.ValidationTimeout)
-			defer cancel()
-
-			res := v.Validate(ctx, result.Email)
-
-			resultsChan <- struct {
-				name string
-				res  *ValidationResult
-			}{vName, res}
-		}(name, validator)
+		}
+		applyOverrides(enabled, policy.Enable, policy.Disable)
+
+		// Validators given in the request take the place of the policy's,
+		// and run even when disabled globally or by the policy
+		if len(opts.Validators) > 0 {
+			clear(enabled)
+			for _, name := range opts.Validators {
+				enabled[name] = true
+			}
+		}
 	}
+	applyOverrides(enabled, opts.Enable, opts.Disable)
 
-	// Close channel when all goroutines complete
-	go func() {
-		wg.Wait()
-		close(resultsChan)
-	}()
-
-	// Collect results
-	for res := range resultsChan {
-		result.Results[res.name] = res.res
+	candidates := opts.Validators
+	if len(candidates) == 0 {
+		for name := range enabled {
+			candidates = append(candidates, name)
+		}
 	}
-}
 
-// getValidatorsToRun determines which validators to execute
-func (e *EmailChecker) getValidatorsToRun(requested []string) []string {
-	if len(requested) == 0 {
-		// Return all enabled validators
-		var enabled []string
-		for name, validator := range e.validators {
-			if validator.IsEnabled() {
-				enabled = append(enabled, name)
-			}
+	// Keep enabled validators that exist
+	var toRun []string
+	for _, name := range candidates {
+		if _, exists := e.validators[name]; exists && enabled[name] {
+			toRun = append(toRun, name)
 		}
-		return enabled
 	}
+	return toRun
+}
 
-	// Filter requested validators to only enabled ones
-	var valid []string
-	for _, name := range requested {
-		if validator, exists := e.validators[name]; exists && validator.IsEnabled() {
-			valid = append(valid, name)
-		}
+// applyOverrides enables and then disables validators in the enabled set
+func applyOverrides(enabled map[string]bool, enable, disable []string) {
+	for _, name := range enable {
+		enabled[name] = true
+	}
+	for _, name := range disable {
+		enabled[name] = false
 	}
-	return valid
 }
 
-// buildCacheKey creates a cache key for the given email and validators
-func (e *EmailChecker) buildCacheKey(email string, validatorNames []string) string {
-	if len(validatorNames) == 0 {
-		return email
+// buildCacheKey creates a cache key for the given email, policy, validators
+// and thresholds, prefixed by the checker's fingerprint
+func (e *EmailChecker) buildCacheKey(email string, opts CheckOptions, thresholds ScoreThresholds) string {
+	e.mu.RLock()
+	key := e.fingerprint + ":" + email
+	e.mu.RUnlock()
+
+	if opts.Policy != "" {
+		key = fmt.Sprintf("%s:policy=%s", key, opts.Policy)
+	}
+	if len(opts.Validators) > 0 {
+		key = fmt.Sprintf("%s:%v", key, opts.Validators)
+	}
+	if len(opts.Enable) > 0 || len(opts.Disable) > 0 {
+		key = fmt.Sprintf("%s:+%v-%v", key, sortedNames(opts.Enable), sortedNames(opts.Disable))
 	}
-	return fmt.Sprintf("%s:%v", email, validatorNames)
+	if thresholds != e.scoreThresholds() {
+		key = fmt.Sprintf("%s:%v", key, thresholds)
+	}
+	return key
+}
+
+// sortedNames returns a sorted copy of names
+func sortedNames(names []string) []string {
+	sorted := append([]string(nil), names...)
+	sort.Strings(sorted)
+	return sorted
 }
 
 // GetValidators returns available validators and their status
 func (e *EmailChecker) GetValidators() map[string]bool {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
 	validators := make(map[string]bool)
 	for name, validator := range e.validators {
 		validators[name] = validator.IsEnabled()
@@ -298,7 +679,8 @@ func (e *EmailChecker) GetValidators() map[string]bool {
 	return validators
 }
 
-// SetValidatorEnabled enables or disables a specific validator
+// SetValidatorEnabled enables or disables a specific validator for every
+// check, use CheckOptions.Enable and Disable to override it for one check
 func (e *EmailChecker) SetValidatorEnabled(name string, enabled bool) error {
 	e.mu.Lock()
 	defer e.mu.Unlock()
@@ -309,11 +691,19 @@ func (e *EmailChecker) SetValidatorEnabled(name string, enabled bool) error {
 	}
 
 	validator.SetEnabled(enabled)
+	e.updateFingerprint()
+
+	// Cached results may have been computed with a different validator set
+	e.cache.Purge()
+
 	return nil
 }
 
 // GetValidatorNames returns all available validator names
 func (e *EmailChecker) GetValidatorNames() []string {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
 	var names []string
 	for name := range e.validators {
 		names = append(names, name)
@@ -323,12 +713,27 @@ func (e *EmailChecker) GetValidatorNames() []string {
 
 // calculateSummary calculates overall validity and summary from validation results
 func (e *EmailChecker) calculateSummary(result *CheckResult) {
-	// Calculate overall validity - email is valid if all enabled validators pass
+	// Calculate overall validity - email is valid if all enabled validators pass.
+	// Kept for backward compatibility, see calculateScore for the verdict
 	result.IsValid = true
 	summary := &CheckSummary{}
 
+	// A check cut short before any validator ran proves nothing
+	if result.Partial && len(result.Results) == 0 {
+		result.IsValid = false
+	}
+
 	for name, validationResult := range result.Results {
-		if !validationResult.Valid {
+		if validationResult.Skipped {
+			// Validators skipped for lack of time or a free slot might have
+			// failed, so a partial check can't pass on their account
+			if result.Partial && !e.policies[result.Policy].isAdvisory(name) {
+				result.IsValid = false
+			}
+			continue
+		}
+
+		if !validationResult.Valid && !e.policies[result.Policy].isAdvisory(name) {
 			result.IsValid = false
 		}
 
@@ -346,8 +751,77 @@ func (e *EmailChecker) calculateSummary(result *CheckResult) {
 			if !validationResult.Valid {
 				summary.IsRole = true
 			}
+		case "suggest":
+			if suggestion, ok := validationResult.Details["suggestion"].(string); ok {
+				result.Suggestion = suggestion
+			}
 		}
 	}
 
 	result.Summary = summary
 }
+
+// LoadReports returns the load report of each list validator, keyed by
+// validator name
+func (e *EmailChecker) LoadReports() map[string]LoadReport {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	reports := make(map[string]LoadReport)
+	for name, validator := range e.validators {
+		if list, ok := asInternal[validators.ListValidator](validator); ok {
+			reports[name] = newLoadReport(list.LoadReport())
+		}
+	}
+	return reports
+}
+
+// newLoadReport converts an internal load report
+func newLoadReport(report loader.LoadReport) LoadReport {
+	entryErrors := make([]LoadError, len(report.Errors))
+	for i, err := range report.Errors {
+		entryErrors[i] = LoadError(err)
+	}
+	return LoadReport{
+		File:       report.File,
+		Entries:    report.Entries,
+		Loaded:     report.Loaded,
+		Duplicates: report.Duplicates,
+		Rejected:   report.Rejected,
+		Errors:     entryErrors,
+		Snapshot:   report.Snapshot,
+	}
+}
+
+// Stats reports runtime statistics of the checker
+func (e *EmailChecker) Stats() *Stats {
+	stats := &Stats{
+		Limiter: e.limiter.stats(),
+	}
+	if e.domainCache != nil {
+		domainStats := DomainCacheStats(e.domainCache.Stats())
+		stats.DomainCache = &domainStats
+	}
+
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	for name, validator := range e.validators {
+		if list, ok := asInternal[validators.ListValidator](validator); ok {
+			if stats.Filters == nil {
+				stats.Filters = make(map[string]FilterStats)
+			}
+			filterStats := list.FilterStats()
+			stats.Filters[name] = FilterStats{
+				Type:              FilterType(filterStats.Type),
+				Items:             filterStats.Items,
+				Capacity:          filterStats.Capacity,
+				Stages:            filterStats.Stages,
+				FillRatio:         filterStats.FillRatio,
+				FalsePositiveRate: filterStats.FalsePositiveRate,
+				MemoryBytes:       filterStats.MemoryBytes,
+			}
+		}
+	}
+	return stats
+}
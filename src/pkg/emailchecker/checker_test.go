@@ -0,0 +1,312 @@
+package emailchecker
+
+import (
+	"context"
+	"slices"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newTestChecker creates a checker running only a validator named "test"
+// backed by fn
+func newTestChecker(t *testing.T, config *Config, fn ValidateFunc) *EmailChecker {
+	t.Helper()
+	return newCheckerWith(t, config, NewValidatorFunc("test", fn))
+}
+
+// newCheckerWith creates a checker running only the given validators
+func newCheckerWith(t *testing.T, config *Config, validators ...Validator) *EmailChecker {
+	t.Helper()
+	if config == nil {
+		config = DefaultConfig()
+	}
+	config.EnabledValidators = []string{}
+
+	checker, err := New(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { checker.Close() })
+	for _, v := range validators {
+		if err := checker.RegisterValidator(v); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return checker
+}
+
+// blockUntilDone is a ValidateFunc that waits for its context to end and
+// reports the validator as failed with the context error
+func blockUntilDone(ctx context.Context, email string) *ValidationResult {
+	<-ctx.Done()
+	return &ValidationResult{Valid: false, Message: "cut short", Error: ctx.Err().Error()}
+}
+
+func TestCheckContextTimeout(t *testing.T) {
+	checker := newTestChecker(t, nil, blockUntilDone)
+
+	start := time.Now()
+	result := checker.CheckContext(context.Background(), "user@example.com", CheckOptions{Timeout: 20 * time.Millisecond})
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Fatalf("check took %v, want it bounded by the 20ms timeout", elapsed)
+	}
+
+	if !result.Partial || result.IsValid {
+		t.Errorf("result partial = %v and valid = %v, want a partial, invalid result", result.Partial, result.IsValid)
+	}
+	if res := result.Results["test"]; res == nil || res.Error != context.DeadlineExceeded.Error() {
+		t.Errorf("validator result = %+v, want it cut short by the deadline", res)
+	}
+}
+
+func TestCheckContextCancel(t *testing.T) {
+	started := make(chan struct{}, 2)
+	var calls atomic.Int32
+	checker := newTestChecker(t, nil, func(ctx context.Context, email string) *ValidationResult {
+		calls.Add(1)
+		started <- struct{}{}
+		return blockUntilDone(ctx, email)
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-started
+		cancel()
+	}()
+
+	result := checker.CheckContext(ctx, "user@example.com", CheckOptions{})
+	if !result.Partial || result.IsValid {
+		t.Errorf("result partial = %v and valid = %v, want a partial, invalid result", result.Partial, result.IsValid)
+	}
+	if result.Verdict == VerdictDeliverable {
+		t.Errorf("verdict = %s for a cancelled check", result.Verdict)
+	}
+
+	// Cancelled checks aren't cached
+	checker.CheckContext(context.Background(), "user@example.com", CheckOptions{Timeout: 10 * time.Millisecond})
+	if calls.Load() != 2 {
+		t.Error("cancelled result served from the cache")
+	}
+}
+
+func TestCheckContextCancelledWaiter(t *testing.T) {
+	started := make(chan struct{})
+	unblock := make(chan struct{})
+	checker := newTestChecker(t, nil, func(ctx context.Context, email string) *ValidationResult {
+		close(started)
+		<-unblock
+		return nil
+	})
+
+	done := make(chan *CheckResult)
+	go func() {
+		done <- checker.Check("user@example.com")
+	}()
+	<-started
+
+	// A caller joining the in-flight check gives up before any validator
+	// result is in, which proves nothing about the email
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	result := checker.CheckContext(ctx, "user@example.com", CheckOptions{})
+	if !result.Partial || result.IsValid || len(result.Results) != 0 {
+		t.Errorf("result partial = %v, valid = %v with %d results, want a partial, invalid result without any",
+			result.Partial, result.IsValid, len(result.Results))
+	}
+	if result.Verdict != VerdictUnknown {
+		t.Errorf("verdict = %s, want %s", result.Verdict, VerdictUnknown)
+	}
+
+	// The shared check carries on for its own caller
+	close(unblock)
+	if result := <-done; result.Partial || !result.IsValid {
+		t.Errorf("shared check partial = %v and valid = %v, want a complete, valid result", result.Partial, result.IsValid)
+	}
+}
+
+func TestCheckCanonicalCacheKey(t *testing.T) {
+	var domainCalls, localCalls atomic.Int32
+	count := func(calls *atomic.Int32) ValidateFunc {
+		return func(ctx context.Context, email string) *ValidationResult {
+			calls.Add(1)
+			return nil
+		}
+	}
+	// Registered under built-in names, so they read what those read
+	checker := newCheckerWith(t, nil,
+		NewValidatorFunc("mx", count(&domainCalls)),
+		NewValidatorFunc("role", count(&localCalls)),
+	)
+
+	// Addresses reaching the same mailbox share results when only the
+	// domain is read
+	mxOnly := CheckOptions{Validators: []string{"mx"}}
+	checker.CheckContext(context.Background(), "john@gmail.com", mxOnly)
+	result := checker.CheckContext(context.Background(), "J.o.h.n+news@gmail.com", mxOnly)
+	if domainCalls.Load() != 1 {
+		t.Errorf("mx ran %d times, want the canonical form served from the cache", domainCalls.Load())
+	}
+	if result.Email != "j.o.h.n+news@gmail.com" || result.CanonicalEmail != "john@gmail.com" {
+		t.Errorf("result email = %s and canonical = %s, want the caller's address", result.Email, result.CanonicalEmail)
+	}
+
+	// The domain as written tells aliases apart
+	checker.CheckContext(context.Background(), "john@googlemail.com", mxOnly)
+	if domainCalls.Load() != 2 {
+		t.Error("result for gmail.com served for googlemail.com")
+	}
+
+	// Validators reading the local part see it as written
+	checker.Check("admin@gmail.com")
+	checker.Check("a.d.m.i.n@gmail.com")
+	checker.Check("admin@gmail.com")
+	if localCalls.Load() != 2 {
+		t.Errorf("role ran %d times, want once for each local part as written", localCalls.Load())
+	}
+
+	// Invalid addresses don't share the results of their canonical form
+	if result := checker.CheckContext(context.Background(), "jo..hn@gmail.com", mxOnly); result.IsValid {
+		t.Error("invalid address served the result of its canonical form")
+	}
+}
+
+func TestValidatorsToRun(t *testing.T) {
+	checker := newCheckerWith(t, nil,
+		NewValidatorFunc("a", nil),
+		NewValidatorFunc("b", nil),
+		NewValidatorFunc("c", nil),
+		NewValidatorFunc("off", nil),
+	)
+	if err := checker.SetValidatorEnabled("off", false); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		policy *Policy
+		opts   CheckOptions
+		want   []string
+	}{
+		{"global defaults", nil, CheckOptions{}, []string{"a", "b", "c"}},
+		{"request validators", nil, CheckOptions{Validators: []string{"a", "off", "unknown"}}, []string{"a"}},
+		{"request enables a disabled validator", nil, CheckOptions{Enable: []string{"off"}}, []string{"a", "b", "c", "off"}},
+		{"request disables", nil, CheckOptions{Disable: []string{"a"}}, []string{"b", "c"}},
+		{"request disable wins over enable", nil, CheckOptions{Enable: []string{"off"}, Disable: []string{"off"}}, []string{"a", "b", "c"}},
+		{"policy validators", &Policy{Validators: []string{"a", "off"}}, CheckOptions{}, []string{"a", "off"}},
+		{"policy overrides", &Policy{Enable: []string{"off"}, Disable: []string{"a"}}, CheckOptions{}, []string{"b", "c", "off"}},
+		{"policy validators and overrides", &Policy{Validators: []string{"a", "b"}, Enable: []string{"c"}, Disable: []string{"a"}}, CheckOptions{}, []string{"b", "c"}},
+		{"request enables what the policy disables", &Policy{Disable: []string{"a"}}, CheckOptions{Enable: []string{"a"}}, []string{"a", "b", "c"}},
+		{"request disables what the policy runs", &Policy{Validators: []string{"a", "b"}}, CheckOptions{Disable: []string{"b"}}, []string{"a"}},
+		{"request validators replace the policy's", &Policy{Validators: []string{"a"}, Disable: []string{"b"}}, CheckOptions{Validators: []string{"b", "off"}}, []string{"b", "off"}},
+		{"request validators and overrides", &Policy{Validators: []string{"a"}}, CheckOptions{Validators: []string{"b", "c"}, Disable: []string{"c"}}, []string{"b"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := sortedNames(checker.getValidatorsToRun(tt.opts, tt.policy))
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("validators to run = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// flightWaiters returns the number of callers waiting on in-flight checks
+func flightWaiters(checker *EmailChecker) int {
+	checker.flightMu.Lock()
+	defer checker.flightMu.Unlock()
+	waiters := 0
+	for _, call := range checker.flights {
+		waiters += call.waiters
+	}
+	return waiters
+}
+
+// waitForWaiters waits until n callers wait on in-flight checks
+func waitForWaiters(t *testing.T, checker *EmailChecker, n int) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for flightWaiters(checker) != n {
+		if time.Now().After(deadline) {
+			t.Fatalf("%d callers waiting, want %d", flightWaiters(checker), n)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestCheckCoalesced(t *testing.T) {
+	const callers = 20
+	var calls atomic.Int32
+	unblock := make(chan struct{})
+	checker := newTestChecker(t, nil, func(ctx context.Context, email string) *ValidationResult {
+		calls.Add(1)
+		<-unblock
+		return nil
+	})
+
+	results := make(chan *CheckResult, callers)
+	for range callers {
+		go func() {
+			results <- checker.Check("user@example.com")
+		}()
+	}
+	waitForWaiters(t, checker, callers)
+	close(unblock)
+
+	for range callers {
+		if result := <-results; !result.IsValid || result.Partial {
+			t.Errorf("result valid = %v and partial = %v, want a complete, valid result", result.IsValid, result.Partial)
+		}
+	}
+	if n := calls.Load(); n != 1 {
+		t.Errorf("validator ran %d times for %d identical checks, want once", n, callers)
+	}
+	if n := flightWaiters(checker); n != 0 {
+		t.Errorf("%d callers still registered after the checks", n)
+	}
+}
+
+func TestCheckCoalescedFirstCallerCancels(t *testing.T) {
+	var calls atomic.Int32
+	started := make(chan struct{})
+	unblock := make(chan struct{})
+	checker := newTestChecker(t, nil, func(ctx context.Context, email string) *ValidationResult {
+		if calls.Add(1) == 1 {
+			close(started)
+		}
+		select {
+		case <-unblock:
+			return nil
+		case <-ctx.Done():
+			return &ValidationResult{Valid: false, Error: ctx.Err().Error()}
+		}
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	first := make(chan *CheckResult)
+	go func() {
+		first <- checker.CheckContext(ctx, "user@example.com", CheckOptions{})
+	}()
+	<-started
+
+	second := make(chan *CheckResult)
+	go func() {
+		second <- checker.Check("user@example.com")
+	}()
+	waitForWaiters(t, checker, 2)
+
+	// The caller that started the check gives up, the one that joined it
+	// still gets the complete result
+	cancel()
+	if result := <-first; !result.Partial {
+		t.Error("cancelled caller got a complete result")
+	}
+	close(unblock)
+	if result := <-second; result.Partial || !result.IsValid {
+		t.Errorf("joined caller got partial = %v and valid = %v, want a complete, valid result", result.Partial, result.IsValid)
+	}
+	if n := calls.Load(); n != 1 {
+		t.Errorf("validator ran %d times, want once", n)
+	}
+}
@@ -0,0 +1,73 @@
+package emailchecker
+
+import "github.com/wizenheimer/bloombox/internal/validators"
+
+// Code is a stable, machine-readable reason for a validation result.
+// Unlike messages, codes do not change when wording changes.
+type Code string
+
+// Syntax codes
+const (
+	CodeSyntaxValid          Code = validators.CodeSyntaxValid
+	CodeSyntaxInvalidAddress Code = validators.CodeSyntaxInvalidAddress
+	CodeSyntaxInvalidLocal   Code = validators.CodeSyntaxInvalidLocal
+	CodeSyntaxInvalidDomain  Code = validators.CodeSyntaxInvalidDomain
+)
+
+// MX codes
+const (
+	CodeMXFound     Code = validators.CodeMXFound
+	CodeMXImplicit  Code = validators.CodeMXImplicit
+	CodeMXNull      Code = validators.CodeMXNull
+	CodeMXNoRecords Code = validators.CodeMXNoRecords
+)
+
+// SMTP codes
+const (
+	CodeSMTPMailboxExists       Code = validators.CodeSMTPMailboxExists
+	CodeSMTPCatchAll            Code = validators.CodeSMTPCatchAll
+	CodeSMTPConnected           Code = validators.CodeSMTPConnected
+	CodeSMTPMailboxNotFound     Code = validators.CodeSMTPMailboxNotFound
+	CodeSMTPUserNotLocal        Code = validators.CodeSMTPUserNotLocal
+	CodeSMTPGreylisted          Code = validators.CodeSMTPGreylisted
+	CodeSMTPRejected            Code = validators.CodeSMTPRejected
+	CodeSMTPConnectionFailed    Code = validators.CodeSMTPConnectionFailed
+	CodeSMTPProtocolError       Code = validators.CodeSMTPProtocolError
+	CodeSMTPSMTPUTF8Unsupported Code = validators.CodeSMTPSMTPUTF8Unsupported
+	CodeSMTPMXLookupFailed      Code = validators.CodeSMTPMXLookupFailed
+	CodeSMTPNullMX              Code = validators.CodeSMTPNullMX
+	CodeSMTPNoMX                Code = validators.CodeSMTPNoMX
+)
+
+// List codes, shared by the disposable, free, role, banwords and
+// blacklist validators
+const (
+	CodeListNotListed          Code = validators.CodeListNotListed
+	CodeListDisposable         Code = validators.CodeListDisposable
+	CodeListFree               Code = validators.CodeListFree
+	CodeListRole               Code = validators.CodeListRole
+	CodeListBannedWord         Code = validators.CodeListBannedWord
+	CodeListBlacklistedEmail   Code = validators.CodeListBlacklistedEmail
+	CodeListBlacklistedDomain  Code = validators.CodeListBlacklistedDomain
+	CodeListBlacklistedPattern Code = validators.CodeListBlacklistedPattern
+)
+
+// Gravatar codes
+const (
+	CodeGravatarFound         Code = validators.CodeGravatarFound
+	CodeGravatarNotFound      Code = validators.CodeGravatarNotFound
+	CodeGravatarRequestFailed Code = validators.CodeGravatarRequestFailed
+)
+
+// Suggestion codes
+const (
+	CodeSuggestNone Code = validators.CodeSuggestNone
+	CodeSuggestTypo Code = validators.CodeSuggestTypo
+)
+
+// Pipeline codes, set on results of validators that did not run
+const (
+	CodePipelineSkipped   Code = "pipeline.skipped"    // An earlier stage or a dependency failed
+	CodePipelineOverload  Code = "pipeline.overloaded" // No validation slot was available in time
+	CodePipelineCancelled Code = "pipeline.cancelled"  // The check was cancelled or timed out
+)
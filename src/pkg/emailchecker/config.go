@@ -3,29 +3,66 @@ package emailchecker
 import (
 	"net"
 	"time"
+
+	"github.com/wizenheimer/bloombox/internal/filter"
 )
 
 // Config holds configuration for the email checker
 type Config struct {
-	// File paths - all optional, validators disabled if files not provided
-	FreeEmailsFile       string `json:"free_emails_file,omitempty"`
-	DisposableEmailsFile string `json:"disposable_emails_file,omitempty"`
-	RoleEmailsFile       string `json:"role_emails_file,omitempty"`
-	BanWordsFile         string `json:"ban_words_file,omitempty"`
-	BlackListEmailsFile  string `json:"blacklist_emails_file,omitempty"`
-	BlackListDomainsFile string `json:"blacklist_domains_file,omitempty"`
+	// File paths or http(s) URLs - all optional, validators disabled if files not provided.
+	// URLs may pin their content with a #sha256=<hex> fragment.
+	FreeEmailsFile        string `json:"free_emails_file,omitempty"`
+	DisposableEmailsFile  string `json:"disposable_emails_file,omitempty"`
+	RoleEmailsFile        string `json:"role_emails_file,omitempty"`
+	BanWordsFile          string `json:"ban_words_file,omitempty"`
+	BlackListEmailsFile   string `json:"blacklist_emails_file,omitempty"`
+	BlackListDomainsFile  string `json:"blacklist_domains_file,omitempty"`
+	BlackListPatternsFile string `json:"blacklist_patterns_file,omitempty"` // Glob and regex rules
+	SuggestDomainsFile    string `json:"suggest_domains_file,omitempty"`    // Extra domains for typo suggestions
+
+	// List download settings, for files given as URLs
+	ListCacheDir     string        `json:"list_cache_dir,omitempty"` // Downloads and fallback copies, defaults to the user cache dir
+	ListFetchTimeout time.Duration `json:"list_fetch_timeout"`
+	MaxListSize      int64         `json:"max_list_size"` // Largest list download in bytes
+
+	// Download lists given as URLs again every ListRefreshInterval and
+	// reload the ones that changed, never when zero. Refreshes run until
+	// Close is called.
+	ListRefreshInterval time.Duration `json:"list_refresh_interval"`
+
+	// Refuse list files with more than MaxListErrorRate invalid entries,
+	// otherwise invalid entries are skipped and reported
+	StrictLists      bool    `json:"strict_lists"`
+	MaxListErrorRate float64 `json:"max_list_error_rate"`
+
+	// Reload list files when they change, watching with inotify where
+	// available and polling every ListPollInterval otherwise. Watchers run
+	// until Close is called.
+	WatchLists       bool          `json:"watch_lists"`
+	ListPollInterval time.Duration `json:"list_poll_interval"`
 
 	// Validator settings
 	EnabledValidators []string `json:"enabled_validators,omitempty"`
 
+	// Named validation policies, selectable per request
+	Policies map[string]Policy `json:"policies,omitempty"`
+
+	// Scoring settings
+	ValidatorWeights map[string]ValidatorWeight `json:"validator_weights,omitempty"` // Overrides built-in weights
+	ScoreThresholds  ScoreThresholds            `json:"score_thresholds"`            // DefaultScoreThresholds if unset
+
 	// Filter settings
-	FalsePositiveRate float64 `json:"false_positive_rate"` // 0 means use map
-	CacheSize         int     `json:"cache_size"`
+	FalsePositiveRate float64               `json:"false_positive_rate"`    // 0 means use map
+	FilterTypes       map[string]FilterType `json:"filter_types,omitempty"` // Per-list filter, keyed by validator name
+	SnapshotDir       string                `json:"snapshot_dir,omitempty"` // Load and save list filter snapshots here
+	CacheSize         int                   `json:"cache_size"`             // Maximum cached results
 
 	// Network settings
 	ValidationTimeout        time.Duration `json:"validation_timeout"`
 	MaxConcurrentValidations int           `json:"max_concurrent_validations"`
-	DialFunc                 DialFunc      `json:"-"` // Custom dial function for proxy
+	MaxQueueLength           int           `json:"max_queue_length"` // Validations allowed to wait for a slot
+	MaxQueueWait             time.Duration `json:"max_queue_wait"`   // How long a validation waits before being skipped
+	DialFunc                 DialFunc      `json:"-"`                // Custom dial function for proxy
 
 	// SMTP settings
 	SMTPTimeout    time.Duration `json:"smtp_timeout"`
@@ -36,22 +73,48 @@ type Config struct {
 
 	// Cache settings
 	CacheTimeout time.Duration `json:"cache_timeout"`
+	CacheFile    string        `json:"cache_file,omitempty"` // Persist results to a disk cache at this path
+	Cache        Cache         `json:"-"`                    // Custom cache backend, overrides CacheFile
+
+	// Domain cache settings, for DNS-derived facts shared across checks
+	DomainCacheSize int           `json:"domain_cache_size"`
+	DomainCacheTTL  time.Duration `json:"domain_cache_ttl"` // 0 disables the domain cache
 }
 
+// FilterType selects the filter backing a list validator
+type FilterType string
+
+const (
+	FilterAuto   FilterType = ""                            // Map for small lists or a 0 rate, hybrid otherwise
+	FilterMap    FilterType = FilterType(filter.TypeMap)    // Exact matching, no false positives
+	FilterBloom  FilterType = FilterType(filter.TypeBloom)  // Smallest footprint
+	FilterCuckoo FilterType = FilterType(filter.TypeCuckoo) // Compact, supports deletes
+	FilterHybrid FilterType = FilterType(filter.TypeHybrid) // Bloom prefilter confirmed by an exact set, no false positives
+)
+
 // DefaultConfig returns a minimal default configuration
 func DefaultConfig() *Config {
 	return &Config{
 		EnabledValidators:        []string{"syntax"}, // Only enable syntax by default
+		ScoreThresholds:          DefaultScoreThresholds(),
 		FalsePositiveRate:        0.01,
+		ListFetchTimeout:         30 * time.Second,
+		MaxListSize:              64 << 20,
+		MaxListErrorRate:         0.01,
+		ListPollInterval:         10 * time.Second,
 		CacheSize:                1000,
 		ValidationTimeout:        5 * time.Second,
 		MaxConcurrentValidations: 10,
+		MaxQueueLength:           100,
+		MaxQueueWait:             2 * time.Second,
 		SMTPTimeout:              5 * time.Second,
 		SMTPFromDomain:           "example.com",
 		SMTPFromEmail:            "test@example.com",
 		EnableSMTPVRFY:           false,
 		EnableSMTPRCPT:           true,
 		CacheTimeout:             10 * time.Minute,
+		DomainCacheSize:          10000,
+		DomainCacheTTL:           time.Hour,
 		DialFunc:                 (&net.Dialer{Timeout: 5 * time.Second}).DialContext,
 	}
 }
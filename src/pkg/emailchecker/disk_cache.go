@@ -0,0 +1,233 @@
+package emailchecker
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+var (
+	// resultsBucket maps cache keys to encoded entries
+	resultsBucket = []byte("results")
+	// expiryBucket indexes cache keys by expiry time, oldest first
+	expiryBucket = []byte("expiry")
+)
+
+// diskEntry is the stored form of a cached result
+type diskEntry struct {
+	Expires time.Time    `json:"expires"`
+	Result  *CheckResult `json:"result"`
+}
+
+// DiskCache is a persistent Cache backed by a bbolt file. Results survive
+// restarts, expire after the TTL, and the entries expiring first are
+// evicted once the size cap is reached.
+type DiskCache struct {
+	db         *bolt.DB
+	ttl        time.Duration
+	maxEntries int
+	count      int
+	mu         sync.Mutex
+}
+
+// NewDiskCache opens or creates a disk cache at path holding up to
+// maxEntries results for ttl
+func NewDiskCache(path string, ttl time.Duration, maxEntries int) (*DiskCache, error) {
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open cache file %s: %w", path, err)
+	}
+
+	cache := &DiskCache{
+		db:         db,
+		ttl:        ttl,
+		maxEntries: maxEntries,
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		results, err := tx.CreateBucketIfNotExists(resultsBucket)
+		if err != nil {
+			return err
+		}
+		if _, err := tx.CreateBucketIfNotExists(expiryBucket); err != nil {
+			return err
+		}
+		cache.count = results.Stats().KeyN
+		return cache.evict(tx)
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to initialize cache file %s: %w", path, err)
+	}
+
+	return cache, nil
+}
+
+// Get returns the cached result for key if it has not expired
+func (c *DiskCache) Get(key string) (*CheckResult, bool) {
+	var entry diskEntry
+	found := false
+
+	c.db.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket(resultsBucket).Get([]byte(key))
+		if data == nil {
+			return nil
+		}
+		if err := json.Unmarshal(data, &entry); err != nil {
+			return err
+		}
+		found = true
+		return nil
+	})
+
+	if !found || entry.Result == nil {
+		return nil, false
+	}
+
+	if time.Now().After(entry.Expires) {
+		c.Remove(key)
+		return nil, false
+	}
+
+	return entry.Result, true
+}
+
+// Add stores a result under key
+func (c *DiskCache) Add(key string, result *CheckResult) {
+	entry := diskEntry{
+		Expires: time.Now().Add(c.ttl),
+		Result:  result,
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.db.Update(func(tx *bolt.Tx) error {
+		if err := c.delete(tx, []byte(key)); err != nil {
+			return err
+		}
+
+		if err := tx.Bucket(resultsBucket).Put([]byte(key), data); err != nil {
+			return err
+		}
+		if err := tx.Bucket(expiryBucket).Put(expiryKey(entry.Expires, key), nil); err != nil {
+			return err
+		}
+		c.count++
+
+		return c.evict(tx)
+	})
+}
+
+// Remove deletes the result stored under key
+func (c *DiskCache) Remove(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.db.Update(func(tx *bolt.Tx) error {
+		return c.delete(tx, []byte(key))
+	})
+}
+
+// Purge deletes all cached results
+func (c *DiskCache) Purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.db.Update(func(tx *bolt.Tx) error {
+		for _, name := range [][]byte{resultsBucket, expiryBucket} {
+			if err := tx.DeleteBucket(name); err != nil {
+				return err
+			}
+			if _, err := tx.CreateBucket(name); err != nil {
+				return err
+			}
+		}
+		c.count = 0
+		return nil
+	})
+}
+
+// Len returns the number of cached results, including expired ones not
+// yet evicted
+func (c *DiskCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.count
+}
+
+// Close closes the underlying cache file
+func (c *DiskCache) Close() error {
+	return c.db.Close()
+}
+
+// delete removes key and its expiry index entry, the caller must hold c.mu
+func (c *DiskCache) delete(tx *bolt.Tx, key []byte) error {
+	results := tx.Bucket(resultsBucket)
+
+	data := results.Get(key)
+	if data == nil {
+		return nil
+	}
+
+	var entry diskEntry
+	if err := json.Unmarshal(data, &entry); err == nil {
+		if err := tx.Bucket(expiryBucket).Delete(expiryKey(entry.Expires, string(key))); err != nil {
+			return err
+		}
+	}
+
+	if err := results.Delete(key); err != nil {
+		return err
+	}
+	c.count--
+
+	return nil
+}
+
+// evict removes expired entries, then the entries expiring first until
+// the cache is within its size cap. The caller must hold c.mu.
+func (c *DiskCache) evict(tx *bolt.Tx) error {
+	results := tx.Bucket(resultsBucket)
+	cursor := tx.Bucket(expiryBucket).Cursor()
+	now := time.Now()
+
+	for k, _ := cursor.First(); k != nil; k, _ = cursor.First() {
+		expires := time.Unix(0, int64(binary.BigEndian.Uint64(k[:8])))
+		if !expires.Before(now) && (c.maxEntries <= 0 || c.count <= c.maxEntries) {
+			break
+		}
+
+		key := append([]byte(nil), k[8:]...)
+		if err := cursor.Delete(); err != nil {
+			return err
+		}
+		if results.Get(key) == nil {
+			continue
+		}
+		if err := results.Delete(key); err != nil {
+			return err
+		}
+		c.count--
+	}
+
+	return nil
+}
+
+// expiryKey builds the expiry index key, sorted by expiry time
+func expiryKey(expires time.Time, key string) []byte {
+	buf := make([]byte, 8+len(key))
+	binary.BigEndian.PutUint64(buf, uint64(expires.UnixNano()))
+	copy(buf[8:], key)
+	return buf
+}
@@ -0,0 +1,106 @@
+package emailchecker
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/wizenheimer/bloombox/internal/loader"
+	"github.com/wizenheimer/bloombox/internal/validators"
+	"github.com/wizenheimer/bloombox/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// updateFingerprint recomputes the fingerprint of what check results depend
+// on beyond their options: the validators, whether each is enabled, their
+// weights, the contents of their list files, the default thresholds, the
+// policy definitions and the SMTP and timeout settings. It prefixes the
+// cache keys, so a persistent cache never serves results of a checker
+// configured differently, such as before a restart. Callers must hold e.mu.
+func (e *EmailChecker) updateFingerprint() {
+	names := make([]string, 0, len(e.validators))
+	for name := range e.validators {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	hash := sha256.New()
+	for _, name := range names {
+		validator := e.validators[name]
+		fmt.Fprintf(hash, "%s=%t,%v;", name, validator.IsEnabled(), e.weightOf(name, validator))
+		if sum, ok := e.listSums[name]; ok {
+			fmt.Fprintf(hash, "%s;", sum)
+		}
+	}
+
+	fmt.Fprintf(hash, "thresholds=%v;", e.scoreThresholds())
+
+	policies := make([]string, 0, len(e.policies))
+	for name := range e.policies {
+		policies = append(policies, name)
+	}
+	sort.Strings(policies)
+	for _, name := range policies {
+		// Policies only hold JSON-friendly fields, encoding can't fail
+		data, _ := json.Marshal(e.policies[name])
+		fmt.Fprintf(hash, "policy=%s;", data)
+	}
+
+	fmt.Fprintf(hash, "timeout=%v;smtp=%v,%s,%s,%t,%t;",
+		e.config.ValidationTimeout,
+		e.config.SMTPTimeout,
+		e.config.SMTPFromDomain,
+		e.config.SMTPFromEmail,
+		e.config.EnableSMTPVRFY,
+		e.config.EnableSMTPRCPT,
+	)
+
+	e.fingerprint = hex.EncodeToString(hash.Sum(nil))[:16]
+}
+
+// updateListSum records the checksum of the list files of the named
+// validator, if it is built from any. Callers must hold e.mu.
+func (e *EmailChecker) updateListSum(name string) {
+	list, ok := asInternal[validators.ReloadableValidator](e.validators[name])
+	if !ok {
+		delete(e.listSums, name)
+		return
+	}
+	files := listFiles(list)
+	if len(files) == 0 {
+		delete(e.listSums, name)
+		return
+	}
+
+	sum, err := listSum(files)
+	if err != nil {
+		// Without a checksum the list still shows up by name, results keep
+		// being cached across restarts as before
+		logger.Warn("Failed to checksum list file",
+			zap.String("validator", name),
+			zap.Error(err),
+		)
+		delete(e.listSums, name)
+		return
+	}
+	e.listSums[name] = sum
+}
+
+// listSum returns the checksum of a list file, or of the checksums of the
+// files of a list built from several
+func listSum(files []string) (string, error) {
+	hash := sha256.New()
+	for _, file := range files {
+		sum, err := loader.Checksum(file)
+		if err != nil {
+			return "", err
+		}
+		if len(files) == 1 {
+			return hex.EncodeToString(sum[:]), nil
+		}
+		hash.Write(sum[:])
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
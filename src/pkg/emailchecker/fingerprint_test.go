@@ -0,0 +1,72 @@
+package emailchecker
+
+import (
+	"testing"
+	"time"
+)
+
+// fingerprintOf returns the fingerprint of a checker built from config
+func fingerprintOf(t *testing.T, config *Config) string {
+	t.Helper()
+	checker, err := New(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer checker.Close()
+	return checker.fingerprint
+}
+
+func TestFingerprint(t *testing.T) {
+	base := fingerprintOf(t, DefaultConfig())
+	if again := fingerprintOf(t, DefaultConfig()); again != base {
+		t.Fatalf("fingerprint = %s for the same config, want %s", again, base)
+	}
+
+	tests := []struct {
+		name   string
+		change func(config *Config)
+	}{
+		{"enabled validators", func(c *Config) { c.EnabledValidators = []string{"syntax", "mx"} }},
+		{"weights", func(c *Config) {
+			c.ValidatorWeights = map[string]ValidatorWeight{"mx": {Weight: 50, Severity: SeverityWarning}}
+		}},
+		{"thresholds", func(c *Config) { c.ScoreThresholds = ScoreThresholds{Deliverable: 90, Risky: 60} }},
+		{"policies", func(c *Config) { c.Policies = map[string]Policy{"signup": {Advisory: []string{"mx"}}} }},
+		{"policy definition", func(c *Config) { c.Policies = map[string]Policy{"signup": {Advisory: []string{"smtp"}}} }},
+		{"validation timeout", func(c *Config) { c.ValidationTimeout = time.Second }},
+		{"smtp timeout", func(c *Config) { c.SMTPTimeout = time.Second }},
+		{"smtp sender", func(c *Config) { c.SMTPFromEmail = "probe@example.org" }},
+		{"smtp vrfy", func(c *Config) { c.EnableSMTPVRFY = true }},
+	}
+
+	seen := map[string]string{base: "default config"}
+	for _, tt := range tests {
+		config := DefaultConfig()
+		tt.change(config)
+		fingerprint := fingerprintOf(t, config)
+		if other, ok := seen[fingerprint]; ok {
+			t.Errorf("changing the %s gives the fingerprint of the %s", tt.name, other)
+		}
+		seen[fingerprint] = tt.name
+	}
+
+	// Unset thresholds fall back to the defaults and score the same
+	config := DefaultConfig()
+	config.ScoreThresholds = ScoreThresholds{}
+	if got := fingerprintOf(t, config); got != base {
+		t.Errorf("fingerprint = %s with unset thresholds, want the default %s", got, base)
+	}
+}
+
+func TestFingerprintRegisteredWeight(t *testing.T) {
+	checker := newCheckerWith(t, nil, NewValidatorFunc("custom", nil))
+	before := checker.fingerprint
+
+	declared := ValidatorWeight{Weight: 100, Severity: SeverityCritical}
+	if err := checker.RegisterValidator(&weightedValidator{NewValidatorFunc("custom", nil), declared}); err != nil {
+		t.Fatal(err)
+	}
+	if checker.fingerprint == before {
+		t.Error("fingerprint unchanged after the validator declared another weight")
+	}
+}
@@ -5,7 +5,8 @@ import (
 	"net"
 )
 
-// Validator interface for all validation types
+// Validator interface for all validation types. IsEnabled and SetEnabled
+// may be called concurrently with Validate.
 type Validator interface {
 	Name() string
 	Validate(ctx context.Context, email string) *ValidationResult
@@ -13,6 +14,29 @@ type Validator interface {
 	SetEnabled(enabled bool)
 }
 
+// StagedValidator is implemented by validators that declare the pipeline
+// stage they run in and the validators they depend on. Validators that do
+// not implement it run in the network stage.
+type StagedValidator interface {
+	Validator
+	Stage() Stage
+	DependsOn() []string
+}
+
+// WeightedValidator is implemented by validators that declare their own
+// scoring weight. Weights in Config.ValidatorWeights take precedence.
+type WeightedValidator interface {
+	Validator
+	Weight() ValidatorWeight
+}
+
+// Unwrapper is implemented by validators that decorate another validator.
+// The checker looks through decorators for the list reloading, load reports
+// and filter statistics of the built-in validator they wrap.
+type Unwrapper interface {
+	Unwrap() Validator
+}
+
 // Filter interface for different filtering implementations
 type Filter interface {
 	Add(item string) error
@@ -0,0 +1,209 @@
+package emailchecker
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Priority classes decide the order in which queued validations get a slot
+type Priority string
+
+const (
+	PriorityHigh   Priority = "high"
+	PriorityNormal Priority = "normal"
+	PriorityLow    Priority = "low"
+)
+
+// rank returns the queue index of a priority, lower is served first
+func (p Priority) rank() int {
+	switch p {
+	case PriorityHigh:
+		return 0
+	case PriorityLow:
+		return 2
+	default:
+		return 1
+	}
+}
+
+var (
+	errQueueFull    = errors.New("validation queue full")
+	errQueueTimeout = errors.New("timed out waiting for a validation slot")
+)
+
+// skipCode returns the reason code for a validator that could not get a slot
+func skipCode(err error) Code {
+	if errors.Is(err, errQueueFull) || errors.Is(err, errQueueTimeout) {
+		return CodePipelineOverload
+	}
+	return CodePipelineCancelled
+}
+
+// LimiterStats reports the state of the validation limiter
+type LimiterStats struct {
+	Capacity       int           `json:"capacity"`         // Maximum concurrent validations
+	InUse          int           `json:"in_use"`           // Validations currently running
+	Queued         int           `json:"queued"`           // Validations currently waiting
+	MaxQueueLength int           `json:"max_queue_length"` // Maximum waiting validations
+	Acquired       uint64        `json:"acquired"`         // Validations that got a slot
+	Waited         uint64        `json:"waited"`           // Validations that had to queue
+	Skipped        uint64        `json:"skipped"`          // Validations skipped under load
+	TotalQueueWait time.Duration `json:"total_queue_wait"`
+	MaxQueueWait   time.Duration `json:"max_queue_wait"`
+}
+
+// waiter is a validation queued for a slot
+type waiter struct {
+	ready   chan struct{}
+	granted bool
+}
+
+// limiter bounds concurrent validations with a priority wait queue
+type limiter struct {
+	mu       sync.Mutex
+	capacity int
+	inUse    int
+	queues   [3][]*waiter
+	queued   int
+	maxQueue int
+	maxWait  time.Duration
+
+	acquired  atomic.Uint64
+	waited    atomic.Uint64
+	skipped   atomic.Uint64
+	totalWait atomic.Int64
+	longest   atomic.Int64
+}
+
+// newLimiter creates a limiter with the given number of slots
+func newLimiter(capacity, maxQueue int, maxWait time.Duration) *limiter {
+	return &limiter{
+		capacity: capacity,
+		maxQueue: maxQueue,
+		maxWait:  maxWait,
+	}
+}
+
+// acquire waits for a slot until one is free, maxWait elapses or ctx is done
+func (l *limiter) acquire(ctx context.Context, priority Priority) error {
+	l.mu.Lock()
+	if l.inUse < l.capacity && l.queued == 0 {
+		l.inUse++
+		l.mu.Unlock()
+		l.acquired.Add(1)
+		return nil
+	}
+
+	if l.queued >= l.maxQueue {
+		l.mu.Unlock()
+		l.skipped.Add(1)
+		return errQueueFull
+	}
+
+	w := &waiter{ready: make(chan struct{})}
+	rank := priority.rank()
+	l.queues[rank] = append(l.queues[rank], w)
+	l.queued++
+	l.mu.Unlock()
+
+	start := time.Now()
+	l.waited.Add(1)
+
+	timer := time.NewTimer(l.maxWait)
+	defer timer.Stop()
+
+	var err error
+	select {
+	case <-w.ready:
+	case <-timer.C:
+		err = errQueueTimeout
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	if err != nil {
+		l.mu.Lock()
+		if w.granted {
+			// A slot was handed over while giving up, keep it
+			err = nil
+		} else {
+			l.remove(rank, w)
+		}
+		l.mu.Unlock()
+	}
+
+	l.recordWait(time.Since(start))
+
+	if err != nil {
+		l.skipped.Add(1)
+		return err
+	}
+
+	l.acquired.Add(1)
+	return nil
+}
+
+// release frees a slot, handing it to the highest priority waiter if any
+func (l *limiter) release() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for rank := range l.queues {
+		if len(l.queues[rank]) == 0 {
+			continue
+		}
+		w := l.queues[rank][0]
+		l.queues[rank] = l.queues[rank][1:]
+		l.queued--
+		w.granted = true
+		close(w.ready)
+		return
+	}
+
+	l.inUse--
+}
+
+// remove drops a waiter from its queue, the caller must hold l.mu
+func (l *limiter) remove(rank int, w *waiter) {
+	queue := l.queues[rank]
+	for i, queued := range queue {
+		if queued == w {
+			l.queues[rank] = append(queue[:i], queue[i+1:]...)
+			l.queued--
+			return
+		}
+	}
+}
+
+// recordWait updates the queue wait statistics
+func (l *limiter) recordWait(wait time.Duration) {
+	l.totalWait.Add(int64(wait))
+	for {
+		longest := l.longest.Load()
+		if int64(wait) <= longest || l.longest.CompareAndSwap(longest, int64(wait)) {
+			return
+		}
+	}
+}
+
+// stats returns a snapshot of the limiter state
+func (l *limiter) stats() LimiterStats {
+	l.mu.Lock()
+	inUse, queued := l.inUse, l.queued
+	l.mu.Unlock()
+
+	return LimiterStats{
+		Capacity:       l.capacity,
+		InUse:          inUse,
+		Queued:         queued,
+		MaxQueueLength: l.maxQueue,
+		Acquired:       l.acquired.Load(),
+		Waited:         l.waited.Load(),
+		Skipped:        l.skipped.Load(),
+		TotalQueueWait: time.Duration(l.totalWait.Load()),
+		MaxQueueWait:   time.Duration(l.longest.Load()),
+	}
+}
@@ -0,0 +1,253 @@
+package emailchecker
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// waitQueued waits until n validations are queued on l
+func waitQueued(t *testing.T, l *limiter, n int) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for l.stats().Queued != n {
+		if time.Now().After(deadline) {
+			t.Fatalf("%d validations queued, want %d", l.stats().Queued, n)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestLimiterAcquireRelease(t *testing.T) {
+	l := newLimiter(2, 0, time.Second)
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		if err := l.acquire(ctx, PriorityNormal); err != nil {
+			t.Fatalf("acquire %d: %v", i, err)
+		}
+	}
+	// No queue, so a third validation is skipped right away
+	if err := l.acquire(ctx, PriorityNormal); !errors.Is(err, errQueueFull) {
+		t.Fatalf("acquire over capacity = %v, want errQueueFull", err)
+	}
+
+	l.release()
+	if err := l.acquire(ctx, PriorityNormal); err != nil {
+		t.Fatalf("acquire after release: %v", err)
+	}
+	l.release()
+	l.release()
+
+	stats := l.stats()
+	if stats.InUse != 0 || stats.Acquired != 3 || stats.Skipped != 1 {
+		t.Errorf("stats = %+v, want 0 in use, 3 acquired and 1 skipped", stats)
+	}
+}
+
+func TestLimiterQueueTimeout(t *testing.T) {
+	l := newLimiter(1, 1, 20*time.Millisecond)
+	ctx := context.Background()
+
+	if err := l.acquire(ctx, PriorityNormal); err != nil {
+		t.Fatal(err)
+	}
+	if err := l.acquire(ctx, PriorityNormal); !errors.Is(err, errQueueTimeout) {
+		t.Fatalf("queued acquire = %v, want errQueueTimeout", err)
+	}
+	if stats := l.stats(); stats.Queued != 0 || stats.Waited != 1 || stats.MaxQueueWait < 20*time.Millisecond {
+		t.Errorf("stats = %+v, want the waiter gone after waiting 20ms", stats)
+	}
+
+	// The slot isn't handed to the waiter that gave up
+	l.release()
+	if stats := l.stats(); stats.InUse != 0 {
+		t.Errorf("%d slots in use after release, want 0", stats.InUse)
+	}
+}
+
+func TestLimiterCancel(t *testing.T) {
+	l := newLimiter(1, 1, time.Minute)
+	if err := l.acquire(context.Background(), PriorityNormal); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errs := make(chan error)
+	go func() { errs <- l.acquire(ctx, PriorityNormal) }()
+	waitQueued(t, l, 1)
+	cancel()
+
+	if err := <-errs; !errors.Is(err, context.Canceled) {
+		t.Fatalf("cancelled acquire = %v, want context.Canceled", err)
+	}
+	if skipCode(context.Canceled) != CodePipelineCancelled || skipCode(errQueueFull) != CodePipelineOverload {
+		t.Error("skip codes don't tell cancellation from overload")
+	}
+}
+
+func TestLimiterHandOff(t *testing.T) {
+	l := newLimiter(1, 1, time.Minute)
+	ctx := context.Background()
+	if err := l.acquire(ctx, PriorityNormal); err != nil {
+		t.Fatal(err)
+	}
+
+	acquired := make(chan error)
+	go func() { acquired <- l.acquire(ctx, PriorityNormal) }()
+	waitQueued(t, l, 1)
+
+	// Releasing hands the slot to the waiter instead of freeing it
+	l.release()
+	if err := <-acquired; err != nil {
+		t.Fatalf("queued acquire: %v", err)
+	}
+	if stats := l.stats(); stats.InUse != 1 || stats.Queued != 0 {
+		t.Errorf("stats = %+v, want the slot held by the waiter", stats)
+	}
+	// A newcomer can't take the slot from the waiter it was handed to
+	short, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	if err := l.acquire(short, PriorityNormal); err == nil {
+		t.Fatal("acquire took a slot handed to a waiter")
+	}
+	l.release()
+}
+
+func TestLimiterPriority(t *testing.T) {
+	l := newLimiter(1, 3, time.Minute)
+	ctx := context.Background()
+	if err := l.acquire(ctx, PriorityNormal); err != nil {
+		t.Fatal(err)
+	}
+
+	order := make(chan Priority, 3)
+	var wg sync.WaitGroup
+	for i, priority := range []Priority{PriorityLow, PriorityNormal, PriorityHigh} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := l.acquire(ctx, priority); err != nil {
+				t.Errorf("acquire %s: %v", priority, err)
+				return
+			}
+			order <- priority
+			l.release()
+		}()
+		waitQueued(t, l, i+1)
+	}
+
+	l.release()
+	wg.Wait()
+	close(order)
+
+	var got []Priority
+	for priority := range order {
+		got = append(got, priority)
+	}
+	want := []Priority{PriorityHigh, PriorityNormal, PriorityLow}
+	if len(got) != len(want) {
+		t.Fatalf("served %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("served %v, want %v", got, want)
+		}
+	}
+}
+
+func TestLimiterConcurrent(t *testing.T) {
+	// Waiters give up while slots are handed to them, slots must neither
+	// leak nor be given out twice
+	const capacity = 4
+	l := newLimiter(capacity, 64, 200*time.Microsecond)
+
+	var running, peak atomic.Int32
+	var wg sync.WaitGroup
+	for i := 0; i < 64; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 200; j++ {
+				ctx, cancel := context.WithTimeout(context.Background(), time.Duration(j%3)*100*time.Microsecond)
+				err := l.acquire(ctx, Priority([]Priority{PriorityHigh, PriorityNormal, PriorityLow}[j%3]))
+				cancel()
+				if err != nil {
+					continue
+				}
+
+				n := running.Add(1)
+				for {
+					p := peak.Load()
+					if n <= p || peak.CompareAndSwap(p, n) {
+						break
+					}
+				}
+				time.Sleep(50 * time.Microsecond)
+				running.Add(-1)
+				l.release()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if p := peak.Load(); p > capacity {
+		t.Errorf("%d validations ran at once, more than the %d slots", p, capacity)
+	}
+	stats := l.stats()
+	if stats.InUse != 0 || stats.Queued != 0 {
+		t.Errorf("stats = %+v after all released, want nothing in use or queued", stats)
+	}
+	if stats.Acquired+stats.Skipped != 64*200 {
+		t.Errorf("%d acquired and %d skipped, want %d in total", stats.Acquired, stats.Skipped, 64*200)
+	}
+}
+
+func TestCheckPartialUnderLoad(t *testing.T) {
+	config := DefaultConfig()
+	config.MaxConcurrentValidations = 1
+	config.MaxQueueLength = 0
+
+	started := make(chan struct{})
+	unblock := make(chan struct{})
+	var calls atomic.Int32
+	checker := newTestChecker(t, config, func(ctx context.Context, email string) *ValidationResult {
+		calls.Add(1)
+		if email == "busy@example.com" {
+			close(started)
+			<-unblock
+		}
+		return nil
+	})
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		checker.Check("busy@example.com")
+	}()
+	<-started
+
+	// The only slot is taken and nothing may queue
+	result := checker.Check("user@example.com")
+	close(unblock)
+	<-done
+
+	if !result.Partial || result.IsValid {
+		t.Errorf("result partial = %v and valid = %v, want a partial, invalid result", result.Partial, result.IsValid)
+	}
+	if res := result.Results["test"]; res == nil || !res.Skipped || res.Code != CodePipelineOverload {
+		t.Errorf("validator result = %+v, want skipped for overload", res)
+	}
+
+	// Partial results aren't cached
+	before := calls.Load()
+	if result := checker.Check("user@example.com"); result.Partial || !result.IsValid {
+		t.Errorf("second check partial = %v and valid = %v, want a complete, valid result", result.Partial, result.IsValid)
+	}
+	if calls.Load() != before+1 {
+		t.Error("partial result served from the cache")
+	}
+}
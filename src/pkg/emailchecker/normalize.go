@@ -0,0 +1,12 @@
+package emailchecker
+
+import "github.com/wizenheimer/bloombox/internal/normalizer"
+
+// CanonicalEmail returns the canonical form of an email address, so that
+// addresses reaching the same mailbox compare equal. Provider rules are
+// applied for well-known providers: Gmail ignores dots and +tags and
+// googlemail.com is an alias of gmail.com, Outlook and iCloud drop +tags,
+// and Yahoo drops -tags. Other addresses are only lowercased.
+func CanonicalEmail(email string) string {
+	return normalizer.Canonical(email)
+}
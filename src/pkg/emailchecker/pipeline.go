@@ -0,0 +1,213 @@
+package emailchecker
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/wizenheimer/bloombox/internal/validators"
+)
+
+// Stage orders validators by cost, cheaper stages run first
+type Stage int
+
+const (
+	// StageLocal holds in-memory checks such as syntax and list lookups
+	StageLocal Stage = iota
+	// StageDNS holds validators that resolve DNS records
+	StageDNS
+	// StageNetwork holds validators that talk to remote servers
+	StageNetwork
+)
+
+// stageSpec declares the stage of a validator and the validators it depends on
+type stageSpec struct {
+	Stage     Stage
+	DependsOn []string
+}
+
+// defaultStages holds the stages of the built-in validators, validators not
+// listed here run in the network stage unless they implement StagedValidator
+var defaultStages = map[string]stageSpec{
+	"syntax":             {Stage: StageLocal},
+	"disposable":         {Stage: StageLocal},
+	"free":               {Stage: StageLocal},
+	"role":               {Stage: StageLocal},
+	"banwords":           {Stage: StageLocal},
+	"blacklist_emails":   {Stage: StageLocal},
+	"blacklist_domains":  {Stage: StageLocal},
+	"blacklist_patterns": {Stage: StageLocal},
+	"suggest":            {Stage: StageLocal},
+	"mx":                 {Stage: StageDNS},
+	"smtp":               {Stage: StageNetwork, DependsOn: []string{"mx"}},
+	"gravatar":           {Stage: StageNetwork},
+}
+
+// validatorStage returns the stage spec for a validator
+func (e *EmailChecker) validatorStage(name string) stageSpec {
+	if validator, ok := e.Validator(name); ok {
+		if staged, ok := validator.(StagedValidator); ok {
+			return stageSpec{Stage: staged.Stage(), DependsOn: staged.DependsOn()}
+		}
+	}
+	if spec, ok := defaultStages[name]; ok {
+		return spec
+	}
+	return stageSpec{Stage: StageNetwork}
+}
+
+// planStages groups validators by stage, in execution order
+func (e *EmailChecker) planStages(validatorNames []string) [][]string {
+	byStage := make(map[Stage][]string)
+	for _, name := range validatorNames {
+		stage := e.validatorStage(name).Stage
+		byStage[stage] = append(byStage[stage], name)
+	}
+
+	stages := make([]Stage, 0, len(byStage))
+	for stage := range byStage {
+		stages = append(stages, stage)
+	}
+	sort.Slice(stages, func(i, j int) bool { return stages[i] < stages[j] })
+
+	plan := make([][]string, 0, len(stages))
+	for _, stage := range stages {
+		plan = append(plan, byStage[stage])
+	}
+	return plan
+}
+
+// runValidators executes the specified validators stage by stage. Once a
+// critical validator fails, later stages are skipped, and a validator is
+// skipped when one of its dependencies failed.
+func (e *EmailChecker) runValidators(ctx context.Context, result *CheckResult, validatorNames []string, priority Priority) {
+	// Share DNS-derived facts between checks, or at least between the
+	// validators of this check when the domain cache is disabled
+	domainCache := e.domainCache
+	if domainCache == nil {
+		domainCache = validators.NewDomainCache(len(validatorNames), e.config.ValidationTimeout)
+	}
+	ctx = validators.WithDomainCache(ctx, domainCache)
+
+	hardFail := ""
+	for _, stage := range e.planStages(validatorNames) {
+		var toRun []string
+		for _, name := range stage {
+			if reason := e.skipReason(name, result, hardFail); reason != "" {
+				result.Results[name] = &ValidationResult{
+					Valid:   false,
+					Skipped: true,
+					Message: reason,
+					Code:    CodePipelineSkipped,
+				}
+				continue
+			}
+			toRun = append(toRun, name)
+		}
+
+		e.runStage(ctx, result, toRun, priority)
+
+		if hardFail == "" {
+			hardFail = e.findHardFail(result, toRun)
+		}
+	}
+}
+
+// skipReason explains why a validator should not run, or returns an empty
+// string if it should
+func (e *EmailChecker) skipReason(name string, result *CheckResult, hardFail string) string {
+	if hardFail != "" {
+		return fmt.Sprintf("Skipped: %s failed in an earlier stage", hardFail)
+	}
+
+	var failed []string
+	for _, dep := range e.validatorStage(name).DependsOn {
+		if res, ok := result.Results[dep]; ok && !res.Valid && !res.Skipped {
+			failed = append(failed, dep)
+		}
+	}
+	if len(failed) > 0 {
+		return fmt.Sprintf("Skipped: depends on %s which failed", strings.Join(failed, ", "))
+	}
+
+	return ""
+}
+
+// findHardFail returns the first critical validator among names that failed
+func (e *EmailChecker) findHardFail(result *CheckResult, names []string) string {
+	sorted := append([]string(nil), names...)
+	sort.Strings(sorted)
+
+	for _, name := range sorted {
+		res, ok := result.Results[name]
+		if !ok || res.Valid || res.Skipped {
+			continue
+		}
+		if e.validatorWeight(name, result.Policy).Severity == SeverityCritical {
+			return name
+		}
+	}
+	return ""
+}
+
+// runStage executes the validators of a single stage in parallel. Validators
+// wait in the limiter queue for a free slot, and are skipped when none frees
+// up in time, which marks the result as partial.
+func (e *EmailChecker) runStage(ctx context.Context, result *CheckResult, validatorNames []string, priority Priority) {
+	type stageResult struct {
+		name    string
+		res     *ValidationResult
+		partial bool
+	}
+
+	var wg sync.WaitGroup
+	resultsChan := make(chan stageResult, len(validatorNames))
+
+	for _, name := range validatorNames {
+		// Enabled state was settled by getValidatorsToRun
+		validator, exists := e.Validator(name)
+		if !exists {
+			continue
+		}
+
+		wg.Add(1)
+		go func(vName string, v Validator) {
+			defer wg.Done()
+
+			// Wait for a validation slot
+			if err := e.limiter.acquire(ctx, priority); err != nil {
+				resultsChan <- stageResult{vName, &ValidationResult{
+					Valid:   false,
+					Skipped: true,
+					Message: fmt.Sprintf("Skipped: %v", err),
+					Code:    skipCode(err),
+				}, true}
+				return
+			}
+			defer e.limiter.release()
+
+			vctx, cancel := context.WithTimeout(ctx, e.config.ValidationTimeout)
+			defer cancel()
+
+			res := v.Validate(vctx, result.Email)
+
+			resultsChan <- stageResult{vName, res, false}
+		}(name, validator)
+	}
+
+	// Close channel when all goroutines complete
+	go func() {
+		wg.Wait()
+		close(resultsChan)
+	}()
+
+	// Collect results
+	for res := range resultsChan {
+		result.Results[res.name] = res.res
+		if res.partial {
+			result.Partial = true
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package emailchecker
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// stagedValidator runs in a given stage after its dependencies
+type stagedValidator struct {
+	Validator
+	stage     Stage
+	dependsOn []string
+}
+
+func (v *stagedValidator) Stage() Stage { return v.stage }
+
+func (v *stagedValidator) DependsOn() []string { return v.dependsOn }
+
+// pipelineRecorder records the validators of a checker as they run
+type pipelineRecorder struct {
+	mu  sync.Mutex
+	ran []string
+}
+
+// validator returns a staged validator named name that records its run
+// and passes unless fail is set
+func (r *pipelineRecorder) validator(name string, stage Stage, fail bool, dependsOn ...string) Validator {
+	fn := func(ctx context.Context, email string) *ValidationResult {
+		r.mu.Lock()
+		r.ran = append(r.ran, name)
+		r.mu.Unlock()
+		return &ValidationResult{Valid: !fail}
+	}
+	return &stagedValidator{NewValidatorFunc(name, fn), stage, dependsOn}
+}
+
+func TestPipelineStageOrder(t *testing.T) {
+	r := &pipelineRecorder{}
+	checker := newCheckerWith(t, nil,
+		r.validator("network", StageNetwork, false),
+		r.validator("dns", StageDNS, false),
+		r.validator("local1", StageLocal, false),
+		r.validator("local2", StageLocal, false),
+	)
+
+	result := checker.Check("user@example.com")
+	if !result.IsValid || len(result.Results) != 4 {
+		t.Fatalf("valid = %v with %d results, want 4 passing validators", result.IsValid, len(result.Results))
+	}
+
+	// Validators of a stage run in parallel, in any order
+	if len(r.ran) != 4 || !slices.Contains(r.ran[:2], "local1") || !slices.Contains(r.ran[:2], "local2") ||
+		r.ran[2] != "dns" || r.ran[3] != "network" {
+		t.Errorf("validators ran in order %v, want the local ones, then dns, then network", r.ran)
+	}
+}
+
+func TestPipelineDefaultStages(t *testing.T) {
+	checker := newCheckerWith(t, nil, NewValidatorFunc("custom", nil))
+
+	plan := checker.planStages([]string{"custom", "smtp", "mx", "syntax", "disposable", "gravatar"})
+	if len(plan) != 3 {
+		t.Fatalf("plan = %v, want 3 stages", plan)
+	}
+	want := [][]string{{"syntax", "disposable"}, {"mx"}, {"custom", "smtp", "gravatar"}}
+	for i := range want {
+		got := append([]string(nil), plan[i]...)
+		slices.Sort(got)
+		slices.Sort(want[i])
+		if !slices.Equal(got, want[i]) {
+			t.Errorf("stage %d = %v, want %v", i, got, want[i])
+		}
+	}
+
+	// Built-in dependencies hold without StagedValidator
+	if deps := checker.validatorStage("smtp").DependsOn; !slices.Equal(deps, []string{"mx"}) {
+		t.Errorf("smtp depends on %v, want [mx]", deps)
+	}
+}
+
+func TestPipelineDependencySkip(t *testing.T) {
+	r := &pipelineRecorder{}
+	checker := newCheckerWith(t, nil,
+		r.validator("lookup", StageDNS, true),
+		r.validator("probe", StageNetwork, false, "lookup"),
+		r.validator("other", StageNetwork, false),
+	)
+
+	result := checker.Check("user@example.com")
+
+	res := result.Results["probe"]
+	if res == nil || !res.Skipped || res.Code != CodePipelineSkipped || !strings.Contains(res.Message, "lookup") {
+		t.Fatalf("probe result = %+v, want skipped because lookup failed", res)
+	}
+	if slices.Contains(r.ran, "probe") {
+		t.Error("probe ran although its dependency failed")
+	}
+	// A warning failure doesn't stop the validators that don't depend on it
+	if !slices.Contains(r.ran, "other") {
+		t.Error("other was skipped after a non-critical failure")
+	}
+	if result.Partial {
+		t.Error("result marked partial for a dependency skip")
+	}
+}
+
+func TestPipelineCriticalShortCircuit(t *testing.T) {
+	config := DefaultConfig()
+	config.ValidatorWeights = map[string]ValidatorWeight{
+		"blocked": {Weight: 100, Severity: SeverityCritical},
+	}
+	r := &pipelineRecorder{}
+	checker := newCheckerWith(t, config,
+		r.validator("blocked", StageLocal, true),
+		r.validator("sibling", StageLocal, false),
+		r.validator("dns", StageDNS, false),
+		r.validator("network", StageNetwork, false),
+	)
+
+	result := checker.Check("user@example.com")
+
+	// Validators in the failing stage have run already
+	if !slices.Contains(r.ran, "sibling") {
+		t.Error("validator in the same stage as the critical failure didn't run")
+	}
+	for _, name := range []string{"dns", "network"} {
+		res := result.Results[name]
+		if res == nil || !res.Skipped || !strings.Contains(res.Message, "blocked failed") {
+			t.Errorf("%s result = %+v, want skipped after the critical failure", name, res)
+		}
+		if slices.Contains(r.ran, name) {
+			t.Errorf("%s ran after a critical failure", name)
+		}
+	}
+	if result.IsValid || result.Verdict != VerdictUndeliverable {
+		t.Errorf("valid = %v and verdict = %s, want an undeliverable email", result.IsValid, result.Verdict)
+	}
+}
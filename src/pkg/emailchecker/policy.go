@@ -0,0 +1,207 @@
+package emailchecker
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"time"
+)
+
+// Policy is a named validation profile that requests can select instead of
+// listing validators. Blocking validators make the email undeliverable
+// when they fail, advisory validators are reported but never affect
+// is_valid, the score or the verdict.
+type Policy struct {
+	Name        string           `json:"name"`
+	Description string           `json:"description,omitempty"`
+	Validators  []string         `json:"validators"`           // Validators to run, all enabled validators if empty
+	Enable      []string         `json:"enable,omitempty"`     // Validators to run in addition
+	Disable     []string         `json:"disable,omitempty"`    // Validators to leave out
+	Blocking    []string         `json:"blocking,omitempty"`   // Validators whose failure is critical
+	Advisory    []string         `json:"advisory,omitempty"`   // Validators that never fail the email
+	Thresholds  *ScoreThresholds `json:"thresholds,omitempty"` // Verdict thresholds, config defaults if nil
+	Timeout     time.Duration    `json:"timeout,omitempty"`    // Deadline for the whole check, none if zero
+}
+
+// policyJSON is the wire form of a Policy, with a human-readable timeout
+type policyJSON struct {
+	Name        string           `json:"name"`
+	Description string           `json:"description,omitempty"`
+	Validators  []string         `json:"validators"`
+	Enable      []string         `json:"enable,omitempty"`
+	Disable     []string         `json:"disable,omitempty"`
+	Blocking    []string         `json:"blocking,omitempty"`
+	Advisory    []string         `json:"advisory,omitempty"`
+	Thresholds  *ScoreThresholds `json:"thresholds,omitempty"`
+	Timeout     string           `json:"timeout,omitempty"`
+}
+
+// MarshalJSON encodes the timeout as a duration string such as "3s"
+func (p Policy) MarshalJSON() ([]byte, error) {
+	wire := policyJSON{
+		Name:        p.Name,
+		Description: p.Description,
+		Validators:  p.Validators,
+		Enable:      p.Enable,
+		Disable:     p.Disable,
+		Blocking:    p.Blocking,
+		Advisory:    p.Advisory,
+		Thresholds:  p.Thresholds,
+	}
+	if p.Timeout > 0 {
+		wire.Timeout = p.Timeout.String()
+	}
+	return json.Marshal(wire)
+}
+
+// UnmarshalJSON decodes a policy with a duration string timeout
+func (p *Policy) UnmarshalJSON(data []byte) error {
+	var wire policyJSON
+	if err := json.Unmarshal(data, &wire); err != nil {
+		return err
+	}
+
+	*p = Policy{
+		Name:        wire.Name,
+		Description: wire.Description,
+		Validators:  wire.Validators,
+		Enable:      wire.Enable,
+		Disable:     wire.Disable,
+		Blocking:    wire.Blocking,
+		Advisory:    wire.Advisory,
+		Thresholds:  wire.Thresholds,
+	}
+	if wire.Timeout != "" {
+		timeout, err := time.ParseDuration(wire.Timeout)
+		if err != nil {
+			return fmt.Errorf("invalid timeout %q: %w", wire.Timeout, err)
+		}
+		p.Timeout = timeout
+	}
+	return nil
+}
+
+// validate reports configuration mistakes in the policy
+func (p *Policy) validate() error {
+	for _, name := range p.Blocking {
+		if slices.Contains(p.Advisory, name) {
+			return fmt.Errorf("validator %s is both blocking and advisory", name)
+		}
+	}
+	if p.Thresholds != nil && p.Thresholds.Risky > p.Thresholds.Deliverable {
+		return fmt.Errorf("risky threshold %v is above deliverable threshold %v",
+			p.Thresholds.Risky, p.Thresholds.Deliverable)
+	}
+	if p.Timeout < 0 {
+		return fmt.Errorf("negative timeout %v", p.Timeout)
+	}
+	return nil
+}
+
+// weight returns the weight of a validator overridden by the policy
+func (p *Policy) weight(name string, weight ValidatorWeight) ValidatorWeight {
+	switch {
+	case slices.Contains(p.Blocking, name):
+		return ValidatorWeight{Weight: max(weight.Weight, 100), Severity: SeverityCritical}
+	case slices.Contains(p.Advisory, name):
+		return ValidatorWeight{Weight: 0, Severity: SeverityInfo}
+	}
+	return weight
+}
+
+// isAdvisory reports whether a failure of the validator is ignored
+func (p *Policy) isAdvisory(name string) bool {
+	return p != nil && slices.Contains(p.Advisory, name)
+}
+
+// LoadPolicies reads named policies from a JSON file holding either an
+// array of policies or an object keyed by policy name
+func LoadPolicies(filename string) (map[string]Policy, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read policies file: %w", err)
+	}
+
+	policies := make(map[string]Policy)
+	var list []Policy
+	if err := json.Unmarshal(data, &list); err == nil {
+		for _, policy := range list {
+			policies[policy.Name] = policy
+		}
+		return policies, nil
+	}
+
+	if err := json.Unmarshal(data, &policies); err != nil {
+		return nil, fmt.Errorf("failed to parse policies file: %w", err)
+	}
+	return policies, nil
+}
+
+// initializePolicies validates the configured policies, naming each one
+// after its key. Policies naming a validator the checker doesn't have are
+// refused, since the validator would be left out of checks without notice.
+func (e *EmailChecker) initializePolicies() error {
+	e.policies = make(map[string]*Policy, len(e.config.Policies))
+	for name, policy := range e.config.Policies {
+		if name == "" {
+			return fmt.Errorf("policy name is required")
+		}
+		policy.Name = name
+		if err := policy.validate(); err != nil {
+			return fmt.Errorf("invalid policy %s: %w", name, err)
+		}
+		for _, names := range [][]string{policy.Validators, policy.Enable, policy.Disable, policy.Blocking, policy.Advisory} {
+			for _, validator := range names {
+				if _, exists := e.validators[validator]; !exists {
+					return fmt.Errorf("invalid policy %s: unknown validator %s", name, validator)
+				}
+			}
+		}
+		e.policies[name] = &policy
+	}
+	return nil
+}
+
+// Policy returns the named policy
+func (e *EmailChecker) Policy(name string) (Policy, bool) {
+	policy, ok := e.policies[name]
+	if !ok {
+		return Policy{}, false
+	}
+	return *policy, true
+}
+
+// Policies returns all configured policies sorted by name
+func (e *EmailChecker) Policies() []Policy {
+	policies := make([]Policy, 0, len(e.policies))
+	for _, policy := range e.policies {
+		policies = append(policies, *policy)
+	}
+	sort.Slice(policies, func(i, j int) bool {
+		return policies[i].Name < policies[j].Name
+	})
+	return policies
+}
+
+// applyPolicy fills options left unset by the request from the selected
+// policy, which is nil when none is selected. Selecting a policy that isn't
+// configured is an error.
+func (e *EmailChecker) applyPolicy(opts CheckOptions) (CheckOptions, *Policy, error) {
+	if opts.Policy == "" {
+		return opts, nil, nil
+	}
+	policy, ok := e.policies[opts.Policy]
+	if !ok {
+		return opts, nil, fmt.Errorf("unknown policy %s", opts.Policy)
+	}
+
+	if opts.Thresholds == nil {
+		opts.Thresholds = policy.Thresholds
+	}
+	if opts.Timeout == 0 {
+		opts.Timeout = policy.Timeout
+	}
+	return opts, policy, nil
+}
@@ -0,0 +1,184 @@
+package emailchecker
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestPolicyValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  Policy
+		wantErr string
+	}{
+		{"valid", Policy{Blocking: []string{"mx"}, Advisory: []string{"smtp"}, Timeout: time.Second}, ""},
+		{"blocking and advisory", Policy{Blocking: []string{"mx"}, Advisory: []string{"mx"}}, "both blocking and advisory"},
+		{"inverted thresholds", Policy{Thresholds: &ScoreThresholds{Deliverable: 50, Risky: 80}}, "above deliverable threshold"},
+		{"negative timeout", Policy{Timeout: -time.Second}, "negative timeout"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.policy.validate()
+			if tt.wantErr == "" && err != nil {
+				t.Errorf("validate() = %v, want no error", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Errorf("validate() = %v, want an error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestInitializePolicies(t *testing.T) {
+	tests := []struct {
+		name     string
+		policies map[string]Policy
+		wantErr  string
+	}{
+		{"valid", map[string]Policy{"signup": {Validators: []string{"syntax", "mx"}, Advisory: []string{"mx"}}}, ""},
+		{"empty name", map[string]Policy{"": {}}, "policy name is required"},
+		{"invalid policy", map[string]Policy{"signup": {Timeout: -time.Second}}, "invalid policy signup"},
+		{"unknown validator", map[string]Policy{"signup": {Validators: []string{"syntax", "disposable"}}}, "unknown validator disposable"},
+		{"unknown blocking validator", map[string]Policy{"signup": {Blocking: []string{"typo"}}}, "unknown validator typo"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := DefaultConfig()
+			config.Policies = tt.policies
+			checker, err := New(config)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("New() = %v, want no error", err)
+				}
+				defer checker.Close()
+				// Policies are named after their key
+				if policy, ok := checker.Policy("signup"); !ok || policy.Name != "signup" {
+					t.Errorf("Policy(signup) = %+v, %v, want the policy named signup", policy, ok)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("New() = %v, want an error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoadPolicies(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, data string) string {
+		t.Helper()
+		filename := filepath.Join(dir, name)
+		if err := os.WriteFile(filename, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return filename
+	}
+
+	want := Policy{
+		Name:       "signup",
+		Validators: []string{"syntax", "mx"},
+		Advisory:   []string{"mx"},
+		Thresholds: &ScoreThresholds{Deliverable: 90, Risky: 60},
+		Timeout:    3 * time.Second,
+	}
+	for name, data := range map[string]string{
+		"array.json":  `[{"name": "signup", "validators": ["syntax", "mx"], "advisory": ["mx"], "thresholds": {"deliverable": 90, "risky": 60}, "timeout": "3s"}]`,
+		"object.json": `{"signup": {"name": "signup", "validators": ["syntax", "mx"], "advisory": ["mx"], "thresholds": {"deliverable": 90, "risky": 60}, "timeout": "3s"}}`,
+	} {
+		policies, err := LoadPolicies(write(name, data))
+		if err != nil {
+			t.Fatalf("LoadPolicies(%s): %v", name, err)
+		}
+		got, ok := policies["signup"]
+		if !ok || len(policies) != 1 {
+			t.Fatalf("LoadPolicies(%s) = %+v, want the signup policy", name, policies)
+		}
+		if got.Name != want.Name || strings.Join(got.Validators, ",") != "syntax,mx" ||
+			strings.Join(got.Advisory, ",") != "mx" || *got.Thresholds != *want.Thresholds || got.Timeout != want.Timeout {
+			t.Errorf("LoadPolicies(%s) = %+v, want %+v", name, got, want)
+		}
+	}
+
+	for name, data := range map[string]string{
+		"bad-timeout.json": `[{"name": "signup", "timeout": "soon"}]`,
+		"bad-json.json":    `{"signup": [`,
+	} {
+		if _, err := LoadPolicies(write(name, data)); err == nil {
+			t.Errorf("LoadPolicies(%s) succeeded, want an error", name)
+		}
+	}
+	if _, err := LoadPolicies(filepath.Join(dir, "missing.json")); err == nil {
+		t.Error("LoadPolicies succeeded for a missing file")
+	}
+}
+
+func TestCheckPolicy(t *testing.T) {
+	config := DefaultConfig()
+	config.Policies = map[string]Policy{
+		"lenient": {Advisory: []string{"gravatar"}, Thresholds: &ScoreThresholds{Deliverable: 95, Risky: 90}},
+		"strict":  {Blocking: []string{"gravatar"}},
+	}
+	// Policies may only name validators that exist when the checker is
+	// built, so the failing validator replaces a built-in one
+	checker := newCheckerWith(t, config, NewValidatorFunc("gravatar", func(ctx context.Context, email string) *ValidationResult {
+		return failing()
+	}))
+
+	result := checker.CheckContext(context.Background(), "user@example.com", CheckOptions{Policy: "lenient"})
+	if !result.IsValid || result.Verdict != VerdictDeliverable || result.Policy != "lenient" {
+		t.Errorf("lenient result valid = %v and verdict = %s, want an advisory failure to pass", result.IsValid, result.Verdict)
+	}
+
+	result = checker.CheckContext(context.Background(), "user@example.com", CheckOptions{Policy: "strict"})
+	if result.IsValid || result.Verdict != VerdictUndeliverable {
+		t.Errorf("strict result valid = %v and verdict = %s, want a blocking failure to fail", result.IsValid, result.Verdict)
+	}
+}
+
+func TestCheckUnknownPolicy(t *testing.T) {
+	var calls int
+	checker := newTestChecker(t, nil, func(ctx context.Context, email string) *ValidationResult {
+		calls++
+		return nil
+	})
+
+	result := checker.CheckContext(context.Background(), "user@example.com", CheckOptions{Policy: "sigup"})
+	if result.IsValid || result.Verdict != VerdictUnknown || !strings.Contains(result.Error, "unknown policy sigup") {
+		t.Errorf("result valid = %v, verdict = %s and error = %q, want an invalid result naming the policy",
+			result.IsValid, result.Verdict, result.Error)
+	}
+	if calls != 0 || len(result.Results) != 0 {
+		t.Error("validators ran for an unknown policy")
+	}
+}
+
+func TestCheckPolicyRequestValidators(t *testing.T) {
+	config := DefaultConfig()
+	config.Policies = map[string]Policy{
+		"signup": {Validators: []string{"syntax", "mx"}, Disable: []string{"gravatar"}},
+	}
+	var calls atomic.Int32
+	checker := newCheckerWith(t, config, NewValidatorFunc("gravatar", func(ctx context.Context, email string) *ValidationResult {
+		calls.Add(1)
+		return failing()
+	}))
+
+	// The request's validators replace the policy's, even one the policy
+	// disables
+	result := checker.CheckContext(context.Background(), "user@example.com", CheckOptions{
+		Policy:     "signup",
+		Validators: []string{"gravatar"},
+	})
+	if len(result.Results) != 1 || result.Results["gravatar"] == nil || calls.Load() != 1 {
+		t.Fatalf("results = %v, want only gravatar to run", result.Results)
+	}
+	if result.IsValid || result.Verdict == VerdictUnknown {
+		t.Errorf("valid = %v and verdict = %s, want the gravatar failure scored", result.IsValid, result.Verdict)
+	}
+}
@@ -0,0 +1,66 @@
+package emailchecker
+
+import (
+	"fmt"
+)
+
+// RegisterValidator adds a validator to the checker. A validator registered
+// under the name of an existing one replaces it, which allows decorating
+// the built-in validators obtained through Validator.
+//
+// Registered validators take part in caching, enable/disable, stages,
+// scoring and summaries like the built-in ones. They can implement
+// StagedValidator and WeightedValidator to control how they are scheduled
+// and scored. Decorators of a list validator implement Unwrapper to keep
+// its reloading, load report and filter statistics. When lists are watched,
+// a registered list validator is watched too and the watcher of the
+// validator it replaces is stopped.
+func (e *EmailChecker) RegisterValidator(v Validator) error {
+	if v == nil {
+		return fmt.Errorf("validator cannot be nil")
+	}
+
+	name := v.Name()
+	if name == "" {
+		return fmt.Errorf("validator name cannot be empty")
+	}
+
+	e.mu.Lock()
+	e.validators[name] = v
+	e.watchList(name, v)
+	e.updateListSum(name)
+	e.updateFingerprint()
+	e.mu.Unlock()
+
+	// Cached results may have been computed with a different validator set
+	e.cache.Purge()
+
+	return nil
+}
+
+// UnregisterValidator removes a validator from the checker
+func (e *EmailChecker) UnregisterValidator(name string) error {
+	e.mu.Lock()
+	if _, exists := e.validators[name]; !exists {
+		e.mu.Unlock()
+		return fmt.Errorf("validator %s not found", name)
+	}
+	delete(e.validators, name)
+	e.watchList(name, nil)
+	e.updateListSum(name)
+	e.updateFingerprint()
+	e.mu.Unlock()
+
+	e.cache.Purge()
+
+	return nil
+}
+
+// Validator returns the validator registered under name
+func (e *EmailChecker) Validator(name string) (Validator, bool) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	validator, exists := e.validators[name]
+	return validator, exists
+}
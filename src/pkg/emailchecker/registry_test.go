@@ -0,0 +1,108 @@
+package emailchecker
+
+import (
+	"context"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+)
+
+// countingValidator decorates a validator, counting its validations
+type countingValidator struct {
+	Validator
+	calls atomic.Int32
+}
+
+func (v *countingValidator) Validate(ctx context.Context, email string) *ValidationResult {
+	v.calls.Add(1)
+	return v.Validator.Validate(ctx, email)
+}
+
+func (v *countingValidator) Unwrap() Validator { return v.Validator }
+
+// newListChecker returns a checker running the syntax and disposable
+// validators, with a disposable list holding throwaway.example
+func newListChecker(t *testing.T) *EmailChecker {
+	t.Helper()
+	filename := filepath.Join(t.TempDir(), "disposable.txt")
+	replaceList(t, filename, "throwaway.example")
+
+	config := DefaultConfig()
+	config.DisposableEmailsFile = filename
+	config.EnabledValidators = []string{"syntax", "disposable"}
+	checker, err := New(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { checker.Close() })
+	return checker
+}
+
+func TestUnregisterValidator(t *testing.T) {
+	checker := newListChecker(t)
+	if !isDisposable(checker, "user@throwaway.example") {
+		t.Fatal("disposable list not loaded")
+	}
+	if _, ok := checker.Stats().Filters["disposable"]; !ok {
+		t.Fatal("no filter statistics for the disposable list")
+	}
+
+	if err := checker.UnregisterValidator("disposable"); err != nil {
+		t.Fatal(err)
+	}
+	// The cached result computed with the validator is gone too
+	result := checker.Check("user@throwaway.example")
+	if _, ok := result.Results["disposable"]; ok {
+		t.Error("unregistered validator still in results")
+	}
+	if result.Results["syntax"] == nil {
+		t.Error("remaining validator missing from results")
+	}
+	if _, ok := checker.Stats().Filters["disposable"]; ok {
+		t.Error("unregistered validator still in filter statistics")
+	}
+	if _, ok := checker.LoadReports()["disposable"]; ok {
+		t.Error("unregistered validator still in load reports")
+	}
+	if _, ok := checker.GetValidators()["disposable"]; ok {
+		t.Error("unregistered validator still listed")
+	}
+
+	if err := checker.UnregisterValidator("disposable"); err == nil {
+		t.Error("unregistering an unknown validator succeeded")
+	}
+}
+
+func TestRegisterWrappedValidator(t *testing.T) {
+	checker := newListChecker(t)
+	builtin, ok := checker.Validator("disposable")
+	if !ok {
+		t.Fatal("built-in disposable validator missing")
+	}
+
+	wrapped := &countingValidator{Validator: builtin}
+	if err := checker.RegisterValidator(wrapped); err != nil {
+		t.Fatal(err)
+	}
+	if !isDisposable(checker, "user@throwaway.example") {
+		t.Error("wrapped validator doesn't flag the listed domain")
+	}
+	if n := wrapped.calls.Load(); n != 1 {
+		t.Errorf("wrapped validator ran %d times, want 1", n)
+	}
+
+	// The list features of the built-in validator are found through the
+	// decorator, under its built-in name
+	if stats, ok := checker.Stats().Filters["disposable"]; !ok || stats.Items != 1 {
+		t.Errorf("filter statistics = %+v, %v, want the wrapped list", stats, ok)
+	}
+	if report, ok := checker.LoadReports()["disposable"]; !ok || report.Loaded != 1 {
+		t.Errorf("load report = %+v, %v, want the wrapped list", report, ok)
+	}
+	checker.mu.RLock()
+	_, reloadable := checker.listSums["disposable"]
+	checker.mu.RUnlock()
+	if !reloadable {
+		t.Error("wrapped list left out of the fingerprint")
+	}
+}
@@ -0,0 +1,166 @@
+package emailchecker
+
+import "math"
+
+// Verdict is the overall deliverability classification of an email
+type Verdict string
+
+const (
+	VerdictDeliverable   Verdict = "deliverable"
+	VerdictRisky         Verdict = "risky"
+	VerdictUndeliverable Verdict = "undeliverable"
+	VerdictUnknown       Verdict = "unknown"
+)
+
+// Severity describes how a failing validator affects the verdict
+type Severity string
+
+const (
+	// SeverityInfo failures only lower the score
+	SeverityInfo Severity = "info"
+	// SeverityWarning failures lower the score and cap the verdict at risky
+	SeverityWarning Severity = "warning"
+	// SeverityCritical failures make the email undeliverable
+	SeverityCritical Severity = "critical"
+)
+
+// ValidatorWeight controls how much a failing validator costs
+type ValidatorWeight struct {
+	Weight   float64  `json:"weight"`   // Points subtracted from 100 on failure
+	Severity Severity `json:"severity"` // Effect of a failure on the verdict
+}
+
+// ScoreThresholds map a score to a verdict
+type ScoreThresholds struct {
+	Deliverable float64 `json:"deliverable"` // Minimum score for deliverable
+	Risky       float64 `json:"risky"`       // Minimum score for risky
+}
+
+// defaultWeight applies to validators without a configured weight
+var defaultWeight = ValidatorWeight{Weight: 20, Severity: SeverityWarning}
+
+// defaultValidatorWeights holds the weights of the built-in validators
+var defaultValidatorWeights = map[string]ValidatorWeight{
+	"syntax":             {Weight: 100, Severity: SeverityCritical},
+	"mx":                 {Weight: 100, Severity: SeverityCritical},
+	"blacklist_emails":   {Weight: 100, Severity: SeverityCritical},
+	"blacklist_domains":  {Weight: 100, Severity: SeverityCritical},
+	"blacklist_patterns": {Weight: 100, Severity: SeverityCritical},
+	"disposable":         {Weight: 100, Severity: SeverityCritical},
+	"smtp":               {Weight: 50, Severity: SeverityWarning},
+	"banwords":           {Weight: 40, Severity: SeverityWarning},
+	"role":               {Weight: 20, Severity: SeverityInfo},
+	"free":               {Weight: 10, Severity: SeverityInfo},
+	"gravatar":           {Weight: 5, Severity: SeverityInfo},
+	"suggest":            {Weight: 0, Severity: SeverityInfo},
+}
+
+// DefaultValidatorWeights returns the weights of the built-in validators
+func DefaultValidatorWeights() map[string]ValidatorWeight {
+	weights := make(map[string]ValidatorWeight, len(defaultValidatorWeights))
+	for name, weight := range defaultValidatorWeights {
+		weights[name] = weight
+	}
+	return weights
+}
+
+// DefaultScoreThresholds returns thresholds suited to signup forms
+func DefaultScoreThresholds() ScoreThresholds {
+	return ScoreThresholds{
+		Deliverable: 80,
+		Risky:       50,
+	}
+}
+
+// scoreThresholds returns the configured thresholds, or the defaults when
+// the config leaves them unset, as a Config not built by DefaultConfig does
+func (e *EmailChecker) scoreThresholds() ScoreThresholds {
+	if e.config.ScoreThresholds == (ScoreThresholds{}) {
+		return DefaultScoreThresholds()
+	}
+	return e.config.ScoreThresholds
+}
+
+// validatorWeight returns the weight of a validator under the named policy,
+// which overrides the weight from baseWeight
+func (e *EmailChecker) validatorWeight(name, policy string) ValidatorWeight {
+	weight := e.baseWeight(name)
+	if p, ok := e.policies[policy]; ok {
+		return p.weight(name, weight)
+	}
+	return weight
+}
+
+// baseWeight returns the configured weight for a validator, falling back
+// to the weight declared by the validator and the built-in defaults
+func (e *EmailChecker) baseWeight(name string) ValidatorWeight {
+	validator, _ := e.Validator(name)
+	return e.weightOf(name, validator)
+}
+
+// weightOf is baseWeight for the validator registered under name, nil if
+// there is none, for callers holding e.mu
+func (e *EmailChecker) weightOf(name string, validator Validator) ValidatorWeight {
+	if weight, ok := e.config.ValidatorWeights[name]; ok {
+		return weight
+	}
+	if weighted, ok := validator.(WeightedValidator); ok {
+		return weighted.Weight()
+	}
+	if weight, ok := defaultValidatorWeights[name]; ok {
+		return weight
+	}
+	return defaultWeight
+}
+
+// calculateScore computes the score and verdict from validation results
+func (e *EmailChecker) calculateScore(result *CheckResult, thresholds ScoreThresholds) {
+	score := 100.0
+	scored := 0
+	critical, warning := false, false
+
+	for name, validationResult := range result.Results {
+		// Skipped validators have no say in the score
+		if validationResult.Skipped {
+			continue
+		}
+
+		scored++
+		if validationResult.Valid {
+			continue
+		}
+
+		weight := e.validatorWeight(name, result.Policy)
+		score -= weight.Weight
+
+		switch weight.Severity {
+		case SeverityCritical:
+			critical = true
+		case SeverityWarning:
+			warning = true
+		}
+	}
+
+	if scored == 0 {
+		result.Score = 0
+		result.Verdict = VerdictUnknown
+		return
+	}
+
+	score = math.Max(0, math.Min(100, score))
+	result.Score = int(math.Round(score))
+
+	switch {
+	case critical:
+		result.Verdict = VerdictUndeliverable
+	case result.Partial:
+		// Validators skipped under load could still fail the email
+		result.Verdict = VerdictUnknown
+	case score >= thresholds.Deliverable && !warning:
+		result.Verdict = VerdictDeliverable
+	case score >= thresholds.Risky:
+		result.Verdict = VerdictRisky
+	default:
+		result.Verdict = VerdictUndeliverable
+	}
+}
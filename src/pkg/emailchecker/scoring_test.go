@@ -0,0 +1,203 @@
+package emailchecker
+
+import (
+	"context"
+	"testing"
+)
+
+// failing returns the result of a validator that failed
+func failing() *ValidationResult {
+	return &ValidationResult{Valid: false}
+}
+
+// passing returns the result of a validator that passed
+func passing() *ValidationResult {
+	return &ValidationResult{Valid: true}
+}
+
+func TestCalculateScore(t *testing.T) {
+	checker, err := New(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer checker.Close()
+
+	tests := []struct {
+		name    string
+		results map[string]*ValidationResult
+		partial bool
+		score   int
+		verdict Verdict
+	}{
+		{
+			name:    "all pass",
+			results: map[string]*ValidationResult{"syntax": passing(), "mx": passing(), "free": passing()},
+			score:   100,
+			verdict: VerdictDeliverable,
+		},
+		{
+			name:    "info failures only lower the score",
+			results: map[string]*ValidationResult{"syntax": passing(), "free": failing(), "gravatar": failing()},
+			score:   85,
+			verdict: VerdictDeliverable,
+		},
+		{
+			name:    "info failures below the deliverable threshold",
+			results: map[string]*ValidationResult{"role": failing(), "free": failing(), "gravatar": failing()},
+			score:   65,
+			verdict: VerdictRisky,
+		},
+		{
+			name:    "warning caps the verdict at risky",
+			results: map[string]*ValidationResult{"syntax": passing(), "banwords": failing()},
+			score:   60,
+			verdict: VerdictRisky,
+		},
+		{
+			name:    "warnings below the risky threshold",
+			results: map[string]*ValidationResult{"smtp": failing(), "banwords": failing()},
+			score:   10,
+			verdict: VerdictUndeliverable,
+		},
+		{
+			name:    "critical failure",
+			results: map[string]*ValidationResult{"syntax": passing(), "disposable": failing()},
+			score:   0,
+			verdict: VerdictUndeliverable,
+		},
+		{
+			name:    "unknown validators weigh in as warnings",
+			results: map[string]*ValidationResult{"custom": failing()},
+			score:   80,
+			verdict: VerdictRisky,
+		},
+		{
+			name: "skipped validators have no say",
+			results: map[string]*ValidationResult{
+				"syntax": passing(),
+				"smtp":   {Valid: false, Skipped: true},
+			},
+			score:   100,
+			verdict: VerdictDeliverable,
+		},
+		{
+			name:    "nothing scored",
+			results: map[string]*ValidationResult{"smtp": {Valid: false, Skipped: true}},
+			partial: true,
+			score:   0,
+			verdict: VerdictUnknown,
+		},
+		{
+			name: "partial checks are unknown",
+			results: map[string]*ValidationResult{
+				"syntax": passing(),
+				"smtp":   {Valid: false, Skipped: true},
+			},
+			partial: true,
+			score:   100,
+			verdict: VerdictUnknown,
+		},
+		{
+			name: "partial checks still fail on a critical failure",
+			results: map[string]*ValidationResult{
+				"syntax": failing(),
+				"smtp":   {Valid: false, Skipped: true},
+			},
+			partial: true,
+			score:   0,
+			verdict: VerdictUndeliverable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := &CheckResult{Results: tt.results, Partial: tt.partial}
+			checker.calculateScore(result, DefaultScoreThresholds())
+			if result.Score != tt.score || result.Verdict != tt.verdict {
+				t.Errorf("score = %d and verdict = %s, want %d and %s", result.Score, result.Verdict, tt.score, tt.verdict)
+			}
+		})
+	}
+}
+
+func TestCalculateScoreThresholds(t *testing.T) {
+	checker, err := New(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer checker.Close()
+
+	results := map[string]*ValidationResult{"role": failing(), "free": failing()}
+	tests := []struct {
+		thresholds ScoreThresholds
+		verdict    Verdict
+	}{
+		{ScoreThresholds{Deliverable: 70, Risky: 50}, VerdictDeliverable},
+		{ScoreThresholds{Deliverable: 80, Risky: 50}, VerdictRisky},
+		{ScoreThresholds{Deliverable: 95, Risky: 90}, VerdictUndeliverable},
+	}
+	for _, tt := range tests {
+		result := &CheckResult{Results: results}
+		checker.calculateScore(result, tt.thresholds)
+		if result.Verdict != tt.verdict {
+			t.Errorf("verdict with %+v = %s, want %s", tt.thresholds, result.Verdict, tt.verdict)
+		}
+	}
+}
+
+func TestValidatorWeights(t *testing.T) {
+	config := DefaultConfig()
+	config.ValidatorWeights = map[string]ValidatorWeight{
+		"free": {Weight: 60, Severity: SeverityWarning},
+	}
+	checker, err := New(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer checker.Close()
+
+	// Configured weights override the built-in ones
+	if got := checker.baseWeight("free"); got != config.ValidatorWeights["free"] {
+		t.Errorf("free weight = %+v, want %+v", got, config.ValidatorWeights["free"])
+	}
+	// Validators can declare their own weight
+	declared := ValidatorWeight{Weight: 30, Severity: SeverityCritical}
+	if err := checker.RegisterValidator(&weightedValidator{NewValidatorFunc("custom", nil), declared}); err != nil {
+		t.Fatal(err)
+	}
+	if got := checker.baseWeight("custom"); got != declared {
+		t.Errorf("custom weight = %+v, want %+v", got, declared)
+	}
+	if got := checker.baseWeight("mx"); got != defaultValidatorWeights["mx"] {
+		t.Errorf("mx weight = %+v, want %+v", got, defaultValidatorWeights["mx"])
+	}
+	if got := checker.baseWeight("unknown"); got != defaultWeight {
+		t.Errorf("unknown weight = %+v, want %+v", got, defaultWeight)
+	}
+}
+
+// weightedValidator declares its own scoring weight
+type weightedValidator struct {
+	Validator
+	weight ValidatorWeight
+}
+
+func (v *weightedValidator) Weight() ValidatorWeight { return v.weight }
+
+func TestScoreThresholdsUnset(t *testing.T) {
+	config := DefaultConfig()
+	config.ScoreThresholds = ScoreThresholds{}
+	config.ValidatorWeights = map[string]ValidatorWeight{
+		"test": {Weight: 30, Severity: SeverityInfo},
+	}
+	checker := newTestChecker(t, config, func(ctx context.Context, email string) *ValidationResult {
+		return failing()
+	})
+
+	// Unset thresholds would make any score deliverable
+	result := checker.Check("user@example.com")
+	if result.Score != 70 || result.Verdict != VerdictRisky {
+		t.Errorf("score = %d and verdict = %s, want 70 and %s under the default thresholds",
+			result.Score, result.Verdict, VerdictRisky)
+	}
+}
@@ -0,0 +1,147 @@
+package emailchecker
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// StreamOptions controls a streaming check
+type StreamOptions struct {
+	CheckOptions                      // Applied to every email
+	Workers      int                  // Concurrent checks, MaxConcurrentValidations if zero
+	Ordered      bool                 // Emit results in input order
+	OnProgress   func(StreamProgress) // Called after every emitted result
+}
+
+// StreamProgress reports the progress of a streaming check
+type StreamProgress struct {
+	Processed int           `json:"processed"`
+	Valid     int           `json:"valid"`
+	Invalid   int           `json:"invalid"`
+	Elapsed   time.Duration `json:"elapsed"`
+}
+
+// streamItem is an email, or its result, tagged with its input position
+type streamItem struct {
+	index  int
+	email  string
+	result *CheckResult
+}
+
+// CheckStream validates emails read from in with a bounded pool of workers
+// and sends their results on the returned channel, which is closed once in
+// is closed and drained or ctx is done. Memory use stays bounded however
+// many emails flow through: at most a few results per worker are held,
+// also in ordered mode.
+func (e *EmailChecker) CheckStream(ctx context.Context, in <-chan string, opts StreamOptions) <-chan *CheckResult {
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = e.config.MaxConcurrentValidations
+	}
+	if workers <= 0 {
+		workers = 1
+	}
+
+	out := make(chan *CheckResult, workers)
+	jobs := make(chan streamItem)
+	done := make(chan streamItem, workers)
+
+	// Bounds the emails in flight, including results waiting for their turn
+	// in ordered mode
+	window := make(chan struct{}, workers*2)
+
+	// Feed emails to the workers
+	go func() {
+		defer close(jobs)
+		for index := 0; ; index++ {
+			select {
+			case window <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+
+			select {
+			case email, ok := <-in:
+				if !ok {
+					return
+				}
+				jobs <- streamItem{index: index, email: email}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	// Check emails
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range jobs {
+				job.result = e.CheckContext(ctx, job.email, opts.CheckOptions)
+				done <- job
+			}
+		}()
+	}
+
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	// Emit results, in input order if requested
+	go func() {
+		defer close(out)
+
+		start := time.Now()
+		progress := StreamProgress{}
+
+		emit := func(result *CheckResult) {
+			<-window
+
+			select {
+			case out <- result:
+			case <-ctx.Done():
+				// Nobody is listening anymore, keep draining the workers
+				return
+			}
+
+			progress.Processed++
+			if result.IsValid {
+				progress.Valid++
+			} else {
+				progress.Invalid++
+			}
+			progress.Elapsed = time.Since(start)
+
+			if opts.OnProgress != nil {
+				opts.OnProgress(progress)
+			}
+		}
+
+		pending := make(map[int]*CheckResult)
+		next := 0
+
+		for item := range done {
+			if !opts.Ordered {
+				emit(item.result)
+				continue
+			}
+
+			pending[item.index] = item.result
+			for {
+				result, ok := pending[next]
+				if !ok {
+					break
+				}
+				delete(pending, next)
+				next++
+				emit(result)
+			}
+		}
+	}()
+
+	return out
+}
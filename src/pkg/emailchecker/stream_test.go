@@ -0,0 +1,134 @@
+package emailchecker
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// testEmails returns n distinct emails
+func testEmails(n int) []string {
+	emails := make([]string, n)
+	for i := range emails {
+		emails[i] = fmt.Sprintf("user%d@example.com", i)
+	}
+	return emails
+}
+
+// feed sends emails on a channel closed after the last one
+func feed(emails []string) <-chan string {
+	in := make(chan string)
+	go func() {
+		defer close(in)
+		for _, email := range emails {
+			in <- email
+		}
+	}()
+	return in
+}
+
+// emailIndex returns the position of an email made by testEmails
+func emailIndex(email string) int {
+	var i int
+	fmt.Sscanf(email, "user%d@", &i)
+	return i
+}
+
+func TestCheckStreamOrdered(t *testing.T) {
+	emails := testEmails(200)
+	// Later emails finish first, so results arrive out of order
+	checker := newTestChecker(t, nil, func(ctx context.Context, email string) *ValidationResult {
+		time.Sleep(time.Duration(4-emailIndex(email)%5) * time.Millisecond)
+		return nil
+	})
+
+	var got []string
+	for result := range checker.CheckStream(context.Background(), feed(emails), StreamOptions{Workers: 8, Ordered: true}) {
+		got = append(got, result.Email)
+	}
+
+	if len(got) != len(emails) {
+		t.Fatalf("%d results, want %d", len(got), len(emails))
+	}
+	for i := range emails {
+		if got[i] != emails[i] {
+			t.Fatalf("result %d is for %s, want %s", i, got[i], emails[i])
+		}
+	}
+}
+
+func TestCheckStreamUnordered(t *testing.T) {
+	emails := testEmails(200)
+	const workers = 3
+
+	var running, peak atomic.Int32
+	checker := newTestChecker(t, nil, func(ctx context.Context, email string) *ValidationResult {
+		n := running.Add(1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(time.Duration(emailIndex(email)%3) * time.Millisecond)
+		running.Add(-1)
+		return nil
+	})
+
+	var last StreamProgress
+	opts := StreamOptions{
+		Workers:    workers,
+		OnProgress: func(progress StreamProgress) { last = progress },
+	}
+	seen := make(map[string]bool)
+	for result := range checker.CheckStream(context.Background(), feed(emails), opts) {
+		if seen[result.Email] {
+			t.Fatalf("%s checked twice", result.Email)
+		}
+		seen[result.Email] = true
+	}
+
+	if len(seen) != len(emails) {
+		t.Errorf("%d results, want %d", len(seen), len(emails))
+	}
+	if p := peak.Load(); p > workers {
+		t.Errorf("%d emails checked at once, more than the %d workers", p, workers)
+	}
+	if last.Processed != len(emails) || last.Valid+last.Invalid != len(emails) {
+		t.Errorf("final progress = %+v, want %d processed", last, len(emails))
+	}
+}
+
+func TestCheckStreamCancel(t *testing.T) {
+	checker := newTestChecker(t, nil, func(ctx context.Context, email string) *ValidationResult {
+		<-ctx.Done()
+		return nil
+	})
+
+	// The input is never closed, only cancelling ends the stream
+	in := make(chan string)
+	go func() {
+		for _, email := range testEmails(1000) {
+			in <- email
+		}
+	}()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	out := checker.CheckStream(ctx, in, StreamOptions{Workers: 4, Ordered: true})
+	time.Sleep(10 * time.Millisecond)
+	cancel()
+
+	deadline := time.After(5 * time.Second)
+	for {
+		select {
+		case _, ok := <-out:
+			if !ok {
+				return
+			}
+		case <-deadline:
+			t.Fatal("output not closed after cancelling")
+		}
+	}
+}
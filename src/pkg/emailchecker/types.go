@@ -9,16 +9,102 @@ type CheckRequest struct {
 	Email      string   `json:"email"`
 	Validators []string `json:"validators,omitempty"` // Specific validators to run
 	Timeout    int      `json:"timeout,omitempty"`    // Timeout in seconds
+
+	Thresholds *ScoreThresholds `json:"thresholds,omitempty"` // Verdict thresholds override
+	Priority   Priority         `json:"priority,omitempty"`   // Queue priority under load
+	Policy     string           `json:"policy,omitempty"`     // Named validation policy
+	Enable     []string         `json:"enable,omitempty"`     // Validators to run even if disabled
+	Disable    []string         `json:"disable,omitempty"`    // Validators to leave out
+}
+
+// Options converts the request into check options
+func (r *CheckRequest) Options() CheckOptions {
+	return CheckOptions{
+		Validators: r.Validators,
+		Timeout:    time.Duration(r.Timeout) * time.Second,
+		Thresholds: r.Thresholds,
+		Priority:   r.Priority,
+		Policy:     r.Policy,
+		Enable:     r.Enable,
+		Disable:    r.Disable,
+	}
+}
+
+// CheckOptions controls how a single check is executed
+type CheckOptions struct {
+	Validators []string         // Specific validators to run, all enabled validators if empty
+	Timeout    time.Duration    // Deadline for the whole check, none if zero
+	Thresholds *ScoreThresholds // Verdict thresholds, config defaults if nil
+	Priority   Priority         // Queue priority under load, normal if empty
+	Policy     string           // Named policy supplying defaults, the check fails if unknown
+	Enable     []string         // Validators to run for this check even if disabled
+	Disable    []string         // Validators to leave out of this check
 }
 
 // CheckResult represents the result of an email check
 type CheckResult struct {
-	Email     string                       `json:"email"`
-	Timestamp time.Time                    `json:"timestamp"`
-	Duration  time.Duration                `json:"duration"`
-	Results   map[string]*ValidationResult `json:"results"`
-	IsValid   bool                         `json:"is_valid"`
-	Summary   *CheckSummary                `json:"summary,omitempty"`
+	Email          string                       `json:"email"`
+	Policy         string                       `json:"policy,omitempty"`       // Policy the email was checked with
+	CanonicalEmail string                       `json:"canonical_email"`        // Email after provider normalization
+	Domain         string                       `json:"domain,omitempty"`       // Domain in IDNA U-label form
+	DomainASCII    string                       `json:"domain_ascii,omitempty"` // Domain in IDNA A-label form
+	Timestamp      time.Time                    `json:"timestamp"`
+	Duration       time.Duration                `json:"duration"`
+	Results        map[string]*ValidationResult `json:"results"`
+	IsValid        bool                         `json:"is_valid"`
+	Score          int                          `json:"score"`                // Weighted score from 0 to 100
+	Verdict        Verdict                      `json:"verdict"`              // Deliverability verdict
+	Partial        bool                         `json:"partial,omitempty"`    // Some validators were skipped under load or cut short
+	Suggestion     string                       `json:"suggestion,omitempty"` // Corrected address when the domain looks misspelled
+	Summary        *CheckSummary                `json:"summary,omitempty"`
+	Error          string                       `json:"error,omitempty"` // Why the check couldn't run, such as an unknown policy
+}
+
+// Stats reports runtime statistics of an EmailChecker
+type Stats struct {
+	Limiter     LimiterStats           `json:"limiter"`
+	DomainCache *DomainCacheStats      `json:"domain_cache,omitempty"`
+	Filters     map[string]FilterStats `json:"filters,omitempty"` // Keyed by list validator name
+}
+
+// LoadReport describes how a list file was loaded: how many entries were
+// read, kept, duplicated and rejected, with the first rejected entries
+type LoadReport struct {
+	File       string      `json:"file"`
+	Entries    int         `json:"entries"`
+	Loaded     int         `json:"loaded"`
+	Duplicates int         `json:"duplicates"`
+	Rejected   int         `json:"rejected"`
+	Errors     []LoadError `json:"errors,omitempty"`
+	Snapshot   bool        `json:"snapshot,omitempty"` // Restored from a snapshot, without entry diagnostics
+}
+
+// LoadError is a list entry rejected while loading
+type LoadError struct {
+	Line   int    `json:"line"`
+	Value  string `json:"value"`
+	Reason string `json:"reason"`
+}
+
+// DomainCacheStats reports the state of the domain-level lookup cache
+type DomainCacheStats struct {
+	MXEntries       int    `json:"mx_entries"`
+	HostEntries     int    `json:"host_entries"`
+	CatchAllEntries int    `json:"catch_all_entries"`
+	Hits            uint64 `json:"hits"`
+	Misses          uint64 `json:"misses"`
+}
+
+// FilterStats reports how full a list filter is and its estimated false
+// positive rate
+type FilterStats struct {
+	Type              FilterType `json:"type"`
+	Items             int        `json:"items"`
+	Capacity          int        `json:"capacity,omitempty"` // 0 for map filters, which have no fixed capacity
+	Stages            int        `json:"stages,omitempty"`   // Sub-filters chained as the list grew
+	FillRatio         float64    `json:"fill_ratio"`
+	FalsePositiveRate float64    `json:"false_positive_rate"`
+	MemoryBytes       int        `json:"memory_bytes"` // Approximate for map filters
 }
 
 // CheckSummary provides a quick summary of validation results
@@ -32,9 +118,11 @@ type CheckSummary struct {
 type ValidationResult struct {
 	Valid    bool                   `json:"valid"`
 	Message  string                 `json:"message"`
+	Code     Code                   `json:"code,omitempty"` // Stable reason code, see codes.go
 	Details  map[string]interface{} `json:"details,omitempty"`
 	Duration time.Duration          `json:"duration"`
 	Error    string                 `json:"error,omitempty"`
+	Skipped  bool                   `json:"skipped,omitempty"` // Validator did not run, see Message
 }
 
 // MXRecord represents an MX record
@@ -51,6 +139,7 @@ type SMTPResponse struct {
 	CanReceive bool   `json:"can_receive"`
 	IsMailbox  bool   `json:"is_mailbox"`
 	IsCatchAll bool   `json:"is_catch_all"`
+	SMTPUTF8   bool   `json:"smtputf8"` // Server advertises SMTPUTF8 (RFC 6531)
 }
 
 // SMTPConfig holds SMTP validator configuration
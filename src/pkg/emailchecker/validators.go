@@ -2,8 +2,10 @@ package emailchecker
 
 import (
 	"context"
+	"sync/atomic"
 	"time"
 
+	"github.com/wizenheimer/bloombox/internal/filter"
 	"github.com/wizenheimer/bloombox/internal/validators"
 )
 
@@ -24,26 +26,118 @@ func (v *ValidatorAdapter) SetEnabled(enabled bool) {
 	v.internal.SetEnabled(enabled)
 }
 
+// asInternal returns the built-in validator behind v as a T, looking
+// through decorators that implement Unwrapper
+func asInternal[T any](v Validator) (T, bool) {
+	for v != nil {
+		switch w := v.(type) {
+		case *ValidatorAdapter:
+			internal, ok := w.internal.(T)
+			return internal, ok
+		case Unwrapper:
+			v = w.Unwrap()
+		default:
+			v = nil
+		}
+	}
+	var zero T
+	return zero, false
+}
+
 func (v *ValidatorAdapter) Validate(ctx context.Context, email string) *ValidationResult {
 	result := v.internal.Validate(ctx, email)
 	// Convert validators.ValidationResult to emailchecker.ValidationResult
 	return &ValidationResult{
 		Valid:    result.Valid,
 		Message:  result.Message,
+		Code:     Code(result.Code),
 		Details:  result.Details,
 		Duration: result.Duration,
 		Error:    result.Error,
 	}
 }
 
+// ValidateFunc performs a single validation
+type ValidateFunc func(ctx context.Context, email string) *ValidationResult
+
+// funcValidator implements Validator with a plain function
+type funcValidator struct {
+	name     string
+	validate ValidateFunc
+	enabled  atomic.Bool
+}
+
+// NewValidatorFunc creates an enabled validator named name backed by fn,
+// for use with EmailChecker.RegisterValidator
+func NewValidatorFunc(name string, fn ValidateFunc) Validator {
+	v := &funcValidator{name: name, validate: fn}
+	v.enabled.Store(true)
+	return v
+}
+
+func (v *funcValidator) Name() string { return v.name }
+
+func (v *funcValidator) IsEnabled() bool { return v.enabled.Load() }
+
+func (v *funcValidator) SetEnabled(enabled bool) { v.enabled.Store(enabled) }
+
+func (v *funcValidator) Validate(ctx context.Context, email string) *ValidationResult {
+	start := time.Now()
+	result := v.validate(ctx, email)
+	if result == nil {
+		result = &ValidationResult{Valid: true}
+	}
+	if result.Duration == 0 {
+		result.Duration = time.Since(start)
+	}
+	return result
+}
+
 // NewSyntaxValidator creates a new syntax validator
 func NewSyntaxValidator() Validator {
 	return &ValidatorAdapter{internal: validators.NewSyntaxValidator()}
 }
 
+// ListOptions configures a validator backed by a list filter. The zero
+// value picks the filter by list size, keeps no snapshot and accepts any
+// list, skipping its invalid entries.
+//
+// Reloads are held to MaxErrorRate even when Strict isn't set, and a
+// reloaded file whose entries were all rejected never replaces the
+// current list.
+type ListOptions struct {
+	FilterType   FilterType // Filter holding the list, chosen by list size if empty
+	SnapshotFile string     // Filter snapshot restored at load and rewritten when stale, none if empty
+	Strict       bool       // Refuse lists with more than MaxErrorRate invalid entries
+	MaxErrorRate float64
+}
+
+// maxErrorRate returns the share of invalid entries tolerated
+func (o ListOptions) maxErrorRate() float64 {
+	if o.Strict {
+		return o.MaxErrorRate
+	}
+	return 1
+}
+
+// reloadMaxErrorRate returns the share of invalid entries tolerated when a
+// list is reloaded, MaxErrorRate unless it is unset in lenient mode
+func (o ListOptions) reloadMaxErrorRate() float64 {
+	if o.Strict || o.MaxErrorRate > 0 {
+		return o.MaxErrorRate
+	}
+	return 1
+}
+
 // NewDisposableValidator creates a new disposable email validator
 func NewDisposableValidator(filename string, falsePositiveRate float64) (Validator, error) {
-	internal, err := validators.NewDisposableValidator(filename, falsePositiveRate)
+	return NewDisposableValidatorWithOptions(filename, falsePositiveRate, ListOptions{})
+}
+
+// NewDisposableValidatorWithOptions creates a new disposable email
+// validator configured by opts
+func NewDisposableValidatorWithOptions(filename string, falsePositiveRate float64, opts ListOptions) (Validator, error) {
+	internal, err := validators.NewDisposableValidator(filename, falsePositiveRate, filter.Type(opts.FilterType), opts.SnapshotFile, opts.maxErrorRate(), opts.reloadMaxErrorRate())
 	if err != nil {
 		return nil, err
 	}
@@ -52,7 +146,13 @@ func NewDisposableValidator(filename string, falsePositiveRate float64) (Validat
 
 // NewFreeValidator creates a new free email validator
 func NewFreeValidator(filename string, falsePositiveRate float64) (Validator, error) {
-	internal, err := validators.NewFreeValidator(filename, falsePositiveRate)
+	return NewFreeValidatorWithOptions(filename, falsePositiveRate, ListOptions{})
+}
+
+// NewFreeValidatorWithOptions creates a new free email validator
+// configured by opts
+func NewFreeValidatorWithOptions(filename string, falsePositiveRate float64, opts ListOptions) (Validator, error) {
+	internal, err := validators.NewFreeValidator(filename, falsePositiveRate, filter.Type(opts.FilterType), opts.SnapshotFile, opts.maxErrorRate(), opts.reloadMaxErrorRate())
 	if err != nil {
 		return nil, err
 	}
@@ -79,7 +179,13 @@ func NewBanWordsValidator(filename string) (Validator, error) {
 
 // NewBlackListEmailsValidator creates a new blacklisted emails validator
 func NewBlackListEmailsValidator(filename string, falsePositiveRate float64) (Validator, error) {
-	internal, err := validators.NewBlackListEmailsValidator(filename, falsePositiveRate)
+	return NewBlackListEmailsValidatorWithOptions(filename, falsePositiveRate, ListOptions{})
+}
+
+// NewBlackListEmailsValidatorWithOptions creates a new blacklisted emails
+// validator configured by opts
+func NewBlackListEmailsValidatorWithOptions(filename string, falsePositiveRate float64, opts ListOptions) (Validator, error) {
+	internal, err := validators.NewBlackListEmailsValidator(filename, falsePositiveRate, filter.Type(opts.FilterType), opts.SnapshotFile, opts.maxErrorRate(), opts.reloadMaxErrorRate())
 	if err != nil {
 		return nil, err
 	}
@@ -88,7 +194,23 @@ func NewBlackListEmailsValidator(filename string, falsePositiveRate float64) (Va
 
 // NewBlackListDomainsValidator creates a new blacklisted domains validator
 func NewBlackListDomainsValidator(filename string, falsePositiveRate float64) (Validator, error) {
-	internal, err := validators.NewBlackListDomainsValidator(filename, falsePositiveRate)
+	return NewBlackListDomainsValidatorWithOptions(filename, falsePositiveRate, ListOptions{})
+}
+
+// NewBlackListDomainsValidatorWithOptions creates a new blacklisted
+// domains validator configured by opts
+func NewBlackListDomainsValidatorWithOptions(filename string, falsePositiveRate float64, opts ListOptions) (Validator, error) {
+	internal, err := validators.NewBlackListDomainsValidator(filename, falsePositiveRate, filter.Type(opts.FilterType), opts.SnapshotFile, opts.maxErrorRate(), opts.reloadMaxErrorRate())
+	if err != nil {
+		return nil, err
+	}
+	return &ValidatorAdapter{internal: internal}, nil
+}
+
+// NewBlackListPatternsValidator creates a new validator for glob and regex
+// blacklist rules
+func NewBlackListPatternsValidator(filename string) (Validator, error) {
+	internal, err := validators.NewBlackListPatternsValidator(filename)
 	if err != nil {
 		return nil, err
 	}
@@ -127,3 +249,15 @@ func NewSMTPValidator(config *SMTPConfig) Validator {
 func NewGravatarValidator(timeout time.Duration) Validator {
 	return &ValidatorAdapter{internal: validators.NewGravatarValidator(timeout)}
 }
+
+// NewSuggestValidator creates a new typo suggestion validator correcting
+// domains to popular providers, the domains of filename and those of the
+// providerFiles, such as the free provider list, which never get a
+// suggestion themselves.
+func NewSuggestValidator(filename string, providerFiles ...string) (Validator, error) {
+	internal, err := validators.NewSuggestValidator(filename, providerFiles...)
+	if err != nil {
+		return nil, err
+	}
+	return &ValidatorAdapter{internal: internal}, nil
+}
@@ -0,0 +1,226 @@
+package emailchecker
+
+import (
+	"context"
+	"time"
+
+	"github.com/wizenheimer/bloombox/internal/loader"
+	"github.com/wizenheimer/bloombox/internal/validators"
+	"github.com/wizenheimer/bloombox/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// watchLists starts a watcher for each validator built from a list file,
+// reloading the validator when its file changes until ctx is done.
+// Validators registered later are watched as they are registered.
+func (e *EmailChecker) watchLists(ctx context.Context) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.watchCtx = ctx
+	for name, v := range e.validators {
+		e.watchList(name, v)
+	}
+}
+
+// watchList starts a watcher for the validator registered under name,
+// stopping the watcher of the validator it replaces. It does nothing unless
+// lists are watched and v is built from a list file. Callers hold e.mu.
+func (e *EmailChecker) watchList(name string, v Validator) {
+	if stop, ok := e.watches[name]; ok {
+		stop()
+		delete(e.watches, name)
+	}
+	if e.watchCtx == nil {
+		return
+	}
+	list, ok := asInternal[validators.ReloadableValidator](v)
+	if !ok {
+		return
+	}
+	files := listFiles(list)
+	if len(files) == 0 {
+		return
+	}
+
+	ctx, stop := context.WithCancel(e.watchCtx)
+	e.watches[name] = stop
+
+	// A change to any of the files reloads the whole list
+	for _, file := range files {
+		changes := loader.NewFileWatcher(file).Watch(ctx, e.config.ListPollInterval)
+		go func() {
+			for range changes {
+				e.reloadList(name, list)
+			}
+		}()
+		logger.Debug("Watching list file", zap.String("validator", name), zap.String("file", file))
+	}
+}
+
+// listFiles returns the files a list validator is built from
+func listFiles(list validators.ReloadableValidator) []string {
+	if multi, ok := list.(validators.MultiSourceValidator); ok {
+		return multi.Sources()
+	}
+	if list.Source() == "" {
+		return nil
+	}
+	return []string{list.Source()}
+}
+
+// listSources returns the configured sources of each list validator
+func (e *EmailChecker) listSources() map[string][]string {
+	return map[string][]string{
+		"disposable":         {e.config.DisposableEmailsFile},
+		"free":               {e.config.FreeEmailsFile},
+		"role":               {e.config.RoleEmailsFile},
+		"banwords":           {e.config.BanWordsFile},
+		"blacklist_emails":   {e.config.BlackListEmailsFile},
+		"blacklist_domains":  {e.config.BlackListDomainsFile},
+		"blacklist_patterns": {e.config.BlackListPatternsFile},
+		// The suggest validator also reads the free provider list
+		"suggest": {e.config.FreeEmailsFile, e.config.SuggestDomainsFile},
+	}
+}
+
+// refreshLists downloads the lists given as URLs every
+// ListRefreshInterval until ctx is done. Lists that changed are picked up
+// by the watchers when lists are watched, and reloaded here otherwise.
+func (e *EmailChecker) refreshLists(ctx context.Context) {
+	urls := make(map[string][]string)
+	for name, sources := range e.listSources() {
+		for _, source := range sources {
+			if loader.IsURL(source) {
+				urls[name] = append(urls[name], source)
+			}
+		}
+	}
+	if len(urls) == 0 {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(e.config.ListRefreshInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			for name, sources := range urls {
+				e.refreshList(ctx, name, sources)
+			}
+		}
+	}()
+}
+
+// refreshList downloads the lists of the named validator again, reloading
+// it when their contents changed and lists aren't watched
+func (e *EmailChecker) refreshList(ctx context.Context, name string, sources []string) {
+	e.mu.RLock()
+	list, ok := asInternal[validators.ReloadableValidator](e.validators[name])
+	e.mu.RUnlock()
+	if !ok {
+		return
+	}
+
+	for _, source := range sources {
+		_, err := e.fetchListContext(ctx, source)
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			logger.Error("Failed to refresh list, keeping the current one",
+				zap.String("validator", name),
+				zap.Error(err),
+			)
+			return
+		}
+	}
+	if e.config.WatchLists {
+		return
+	}
+
+	sum, err := listSum(listFiles(list))
+	e.mu.RLock()
+	unchanged := err == nil && sum == e.listSums[name]
+	e.mu.RUnlock()
+	if !unchanged {
+		e.reloadList(name, list)
+	}
+}
+
+// reloadList rebuilds a validator's list, a list that fails to load is
+// logged and the validator keeps its current one
+func (e *EmailChecker) reloadList(name string, list validators.ReloadableValidator) {
+	start := time.Now()
+
+	count, err := list.Reload()
+	if err != nil {
+		logger.Error("Failed to reload list, keeping the current one",
+			zap.String("validator", name),
+			zap.String("file", list.Source()),
+			zap.Error(err),
+		)
+		return
+	}
+
+	e.mu.Lock()
+	e.updateListSum(name)
+	e.updateFingerprint()
+	e.mu.Unlock()
+
+	// Cached results may have been computed against the old list
+	e.cache.Purge()
+
+	if report, ok := list.(validators.ListValidator); ok {
+		logLoadReport("Reloaded list", name, report.LoadReport(), zap.Duration("duration", time.Since(start)))
+		return
+	}
+	logger.Info("Reloaded list",
+		zap.String("validator", name),
+		zap.String("file", list.Source()),
+		zap.Int("items", count),
+		zap.Duration("duration", time.Since(start)),
+	)
+}
+
+// logLoadReports logs the load report of each list validator
+func (e *EmailChecker) logLoadReports() {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	for name, v := range e.validators {
+		if list, ok := asInternal[validators.ListValidator](v); ok {
+			logLoadReport("Loaded list", name, list.LoadReport())
+		}
+	}
+}
+
+// logLoadReport logs the counts of a list load, and the rejected entries as
+// a warning
+func logLoadReport(msg, name string, report loader.LoadReport, fields ...zap.Field) {
+	fields = append([]zap.Field{
+		zap.String("validator", name),
+		zap.String("file", report.File),
+		zap.Int("entries", report.Entries),
+		zap.Int("items", report.Loaded),
+		zap.Int("duplicates", report.Duplicates),
+		zap.Int("rejected", report.Rejected),
+		zap.Bool("snapshot", report.Snapshot),
+	}, fields...)
+	logger.Info(msg, fields...)
+
+	if report.Rejected > 0 {
+		logger.Warn("Skipped invalid list entries",
+			zap.String("validator", name),
+			zap.String("file", report.File),
+			zap.Int("rejected", report.Rejected),
+			zap.Any("entries", report.Errors),
+		)
+	}
+}
@@ -0,0 +1,245 @@
+package emailchecker
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// replaceList writes lines to a temporary file and renames it over
+// filename, the way list files are meant to be replaced
+func replaceList(t *testing.T, filename string, lines ...string) {
+	t.Helper()
+	tmp := filename + ".tmp"
+	if err := os.WriteFile(tmp, []byte(strings.Join(lines, "\n")+"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Rename(tmp, filename); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// waitFor fails the test unless cond holds within a few seconds
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// newWatchingChecker returns a checker watching its list files, polling
+// every pollInterval where it can't be notified
+func newWatchingChecker(t *testing.T, config *Config, pollInterval time.Duration) *EmailChecker {
+	t.Helper()
+	config.EnabledValidators = []string{"syntax", "disposable"}
+	config.WatchLists = true
+	config.ListPollInterval = pollInterval
+	checker, err := New(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { checker.Close() })
+	return checker
+}
+
+// currentFingerprint returns the fingerprint of checker
+func currentFingerprint(checker *EmailChecker) string {
+	checker.mu.RLock()
+	defer checker.mu.RUnlock()
+	return checker.fingerprint
+}
+
+// isDisposable reports whether the disposable validator flags email
+func isDisposable(checker *EmailChecker, email string) bool {
+	result := checker.Check(email).Results["disposable"]
+	return result != nil && !result.Valid
+}
+
+func TestWatchListsReload(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "disposable.txt")
+	replaceList(t, filename, "throwaway.example")
+
+	config := DefaultConfig()
+	config.DisposableEmailsFile = filename
+	// Polling this rarely leaves notifications as the only way to notice,
+	// the poll fallback is covered by the loader tests
+	checker := newWatchingChecker(t, config, time.Hour)
+
+	if !isDisposable(checker, "user@throwaway.example") || isDisposable(checker, "user@burner.example") {
+		t.Fatal("initial list not loaded")
+	}
+	before := currentFingerprint(checker)
+	if checker.cache.Len() == 0 {
+		t.Fatal("checks weren't cached")
+	}
+
+	replaceList(t, filename, "burner.example")
+	// The fingerprint changes before the cache is purged
+	waitFor(t, "the list to reload", func() bool {
+		return currentFingerprint(checker) != before && checker.cache.Len() == 0
+	})
+
+	if isDisposable(checker, "user@throwaway.example") {
+		t.Error("removed domain still flagged after reload")
+	}
+	if !isDisposable(checker, "user@burner.example") {
+		t.Error("added domain not flagged after reload")
+	}
+}
+
+func TestWatchListsKeepsCurrent(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "patterns.txt")
+	replaceList(t, filename, "test-accounts test*@*")
+
+	config := DefaultConfig()
+	config.BlackListPatternsFile = filename
+	config.EnabledValidators = []string{"syntax", "blacklist_patterns"}
+	config.WatchLists = true
+	config.ListPollInterval = 10 * time.Millisecond
+	checker, err := New(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer checker.Close()
+	before := currentFingerprint(checker)
+
+	// A file that fails to load keeps the current list and fingerprint
+	replaceList(t, filename, "bad re:unanchored")
+	time.Sleep(200 * time.Millisecond)
+	if currentFingerprint(checker) != before {
+		t.Error("fingerprint changed after a failed reload")
+	}
+	if checker.Check("tester@example.com").Results["blacklist_patterns"].Valid {
+		t.Error("list dropped after a failed reload")
+	}
+}
+
+func TestWatchListsRefusesBadFile(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "disposable.txt")
+	replaceList(t, filename, "throwaway.example")
+
+	// Lists aren't strict by default, reloads are refused all the same
+	config := DefaultConfig()
+	config.DisposableEmailsFile = filename
+	checker := newWatchingChecker(t, config, 10*time.Millisecond)
+	before := currentFingerprint(checker)
+
+	for _, lines := range [][]string{
+		{"<html>", "<body>502 Bad Gateway</body>", "</html>"},
+		append([]string{"burner.example"}, strings.Fields("not a list of domains at all")...),
+	} {
+		replaceList(t, filename, lines...)
+		time.Sleep(200 * time.Millisecond)
+		if currentFingerprint(checker) != before {
+			t.Error("fingerprint changed after a refused reload")
+		}
+		if !isDisposable(checker, "user@throwaway.example") {
+			t.Errorf("list replaced by a file of %q", lines)
+		}
+	}
+
+	// A good file is picked up again
+	replaceList(t, filename, "burner.example")
+	waitFor(t, "the list to reload", func() bool {
+		return isDisposable(checker, "user@burner.example")
+	})
+}
+
+func TestWatchListsSuggestFile(t *testing.T) {
+	dir := t.TempDir()
+	freeFile := filepath.Join(dir, "free.txt")
+	suggestFile := filepath.Join(dir, "suggest.txt")
+	replaceList(t, freeFile, "gmail.com")
+	replaceList(t, suggestFile, "acmemail.com")
+
+	// The suggest validator reads both files, a change to the one that
+	// isn't its source is picked up too
+	config := DefaultConfig()
+	config.FreeEmailsFile = freeFile
+	config.SuggestDomainsFile = suggestFile
+	config.EnabledValidators = []string{"syntax", "suggest"}
+	config.WatchLists = true
+	config.ListPollInterval = 10 * time.Millisecond
+	checker, err := New(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer checker.Close()
+
+	suggested := func(email string) string {
+		got, _ := checker.Check(email).Results["suggest"].Details["suggested_domain"].(string)
+		return got
+	}
+	if got := suggested("jane@acmemial.com"); got != "acmemail.com" {
+		t.Fatalf("suggested %q, want acmemail.com", got)
+	}
+
+	replaceList(t, suggestFile, "acmemail.com", "widgetco.com")
+	waitFor(t, "the suggestion file to reload", func() bool {
+		return suggested("jane@widgteco.com") == "widgetco.com"
+	})
+}
+
+func TestWatchRegisteredValidator(t *testing.T) {
+	checker := newWatchingChecker(t, DefaultConfig(), time.Hour)
+
+	filename := filepath.Join(t.TempDir(), "disposable.txt")
+	replaceList(t, filename, "throwaway.example")
+	v, err := NewDisposableValidator(filename, 0.01)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := checker.RegisterValidator(v); err != nil {
+		t.Fatal(err)
+	}
+	if !isDisposable(checker, "user@throwaway.example") {
+		t.Fatal("registered list not used")
+	}
+
+	replaceList(t, filename, "burner.example")
+	waitFor(t, "the registered list to reload", func() bool {
+		return isDisposable(checker, "user@burner.example")
+	})
+
+	// Unregistering stops the watcher
+	if err := checker.UnregisterValidator("disposable"); err != nil {
+		t.Fatal(err)
+	}
+	checker.mu.RLock()
+	_, watched := checker.watches["disposable"]
+	checker.mu.RUnlock()
+	if watched {
+		t.Error("unregistered validator still watched")
+	}
+}
+
+func TestWatchDisabled(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "disposable.txt")
+	replaceList(t, filename, "throwaway.example")
+
+	config := DefaultConfig()
+	config.DisposableEmailsFile = filename
+	config.EnabledValidators = []string{"syntax", "disposable"}
+	checker, err := New(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer checker.Close()
+
+	v, err := NewDisposableValidator(filename, 0.01)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := checker.RegisterValidator(v); err != nil {
+		t.Fatal(err)
+	}
+	if len(checker.watches) != 0 {
+		t.Errorf("%d lists watched with WatchLists unset", len(checker.watches))
+	}
+}